@@ -2,10 +2,15 @@ package main
 
 import (
 	"github.com/easc01/mindo-server/internal/handlers"
+	certificateservice "github.com/easc01/mindo-server/internal/services/certificate_service"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/internal/workers"
 	"github.com/easc01/mindo-server/pkg/db"
 )
 
 func main() {
 	db.InitDB()
+	playlistservice.RegisterWatchedHook(certificateservice.IssueEarnedTopicCertificate)
+	workers.StartWorkers()
 	handlers.InitREST()
 }
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/easc01/mindo-server/pkg/logger"
 	"github.com/joho/godotenv"
@@ -33,6 +35,36 @@ type Config struct {
 	GoogleClientId     string
 	GoogleClientSecret string
 	YoutubeAPIKey      string
+	VideoProvider      string
+	PeerTubeURL        string
+	VideoFixturePath   string
+	BlockedWords       string
+	CertificateSecret  string
+
+	PlaylistViewWindow       time.Duration
+	TrendingHalfLife         time.Duration
+	PlaylistStatsInterval    time.Duration
+	SimilarPlaylistsInterval time.Duration
+	VideoRefreshInterval     time.Duration
+	VideoProgressFlush       time.Duration
+	QuizSessionSweep         time.Duration
+	QuizSubmitGrace          time.Duration
+	QuizSessionAbandonAfter  time.Duration
+	LeaderboardInterval      time.Duration
+
+	YoutubeDailyQuota    int
+	YoutubeQuotaReserve  int
+	VideoReportThreshold int
+	VideoWatchedPercent  int
+	ExamPassPercent      int
+
+	VideoIdealDuration      time.Duration
+	VideoRankViewsWeight    float64
+	VideoRankLikesWeight    float64
+	VideoRankCaptionsWeight float64
+	VideoRankHDWeight       float64
+	VideoRankDurationWeight float64
+	VideoRankRecencyWeight  float64
 }
 
 func GetConfig() *Config {
@@ -45,6 +77,36 @@ func GetConfig() *Config {
 		GoogleClientId:     getEnv("GOOGLE_CLIENT_ID", "__GOOGLE_CLIENT_ID__"),
 		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", "__GOOGLE_CLIENT_SECRET__"),
 		YoutubeAPIKey:      getEnv("YOUTUBE_API_KEY", "__YOUTUBE_API_KEY__"),
+		VideoProvider:      getEnv("VIDEO_PROVIDER", "youtube"),
+		PeerTubeURL:        getEnv("PEERTUBE_URL", "https://framatube.org"),
+		VideoFixturePath:   getEnv("VIDEO_FIXTURE_PATH", ""),
+		BlockedWords:       getEnv("MODERATION_BLOCKED_WORDS", ""),
+		CertificateSecret:  getEnv("CERTIFICATE_SECRET", "__CERTIFICATE_SECRET__"),
+
+		PlaylistViewWindow:       getEnvDuration("PLAYLIST_VIEW_WINDOW", 30*time.Minute),
+		TrendingHalfLife:         getEnvDuration("TRENDING_HALF_LIFE", 24*time.Hour),
+		PlaylistStatsInterval:    getEnvDuration("PLAYLIST_STATS_INTERVAL", 10*time.Minute),
+		SimilarPlaylistsInterval: getEnvDuration("SIMILAR_PLAYLISTS_INTERVAL", 6*time.Hour),
+		VideoRefreshInterval:     getEnvDuration("VIDEO_REFRESH_INTERVAL", time.Hour),
+		VideoProgressFlush:       getEnvDuration("VIDEO_PROGRESS_FLUSH_INTERVAL", 15*time.Second),
+		QuizSessionSweep:         getEnvDuration("QUIZ_SESSION_SWEEP_INTERVAL", time.Minute),
+		QuizSubmitGrace:          getEnvDuration("QUIZ_SUBMIT_GRACE", 30*time.Second),
+		QuizSessionAbandonAfter:  getEnvDuration("QUIZ_SESSION_ABANDON_AFTER", 24*time.Hour),
+		LeaderboardInterval:      getEnvDuration("LEADERBOARD_REFRESH_INTERVAL", 5*time.Minute),
+
+		YoutubeDailyQuota:    getEnvInt("YOUTUBE_DAILY_QUOTA", 10000),
+		YoutubeQuotaReserve:  getEnvInt("YOUTUBE_QUOTA_RESERVE", 2000),
+		VideoReportThreshold: getEnvInt("VIDEO_REPORT_THRESHOLD", 3),
+		VideoWatchedPercent:  getEnvInt("VIDEO_WATCHED_PERCENT", 90),
+		ExamPassPercent:      getEnvInt("EXAM_PASS_PERCENT", 60),
+
+		VideoIdealDuration:      getEnvDuration("VIDEO_IDEAL_DURATION", 12*time.Minute),
+		VideoRankViewsWeight:    getEnvFloat("VIDEO_RANK_VIEWS_WEIGHT", 1),
+		VideoRankLikesWeight:    getEnvFloat("VIDEO_RANK_LIKES_WEIGHT", 1),
+		VideoRankCaptionsWeight: getEnvFloat("VIDEO_RANK_CAPTIONS_WEIGHT", 0.5),
+		VideoRankHDWeight:       getEnvFloat("VIDEO_RANK_HD_WEIGHT", 0.25),
+		VideoRankDurationWeight: getEnvFloat("VIDEO_RANK_DURATION_WEIGHT", 1.5),
+		VideoRankRecencyWeight:  getEnvFloat("VIDEO_RANK_RECENCY_WEIGHT", 0.5),
 	}
 }
 
@@ -55,3 +117,45 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		logger.Log.Errorf("invalid duration %s for %s, using %s", value, key, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		logger.Log.Errorf("invalid number %s for %s, using %d", value, key, defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		logger.Log.Errorf("invalid number %s for %s, using %g", value, key, defaultValue)
+		return defaultValue
+	}
+	return number
+}
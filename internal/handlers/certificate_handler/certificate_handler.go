@@ -0,0 +1,115 @@
+package certificatehandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	certificateservice "github.com/easc01/mindo-server/internal/services/certificate_service"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterCertificates(rg *gin.RouterGroup) {
+	certificateRg := rg.Group(route.Certificates)
+
+	{
+		certificateRg.GET(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser),
+			getUserCertificatesHandler,
+		)
+
+		// Public, partners verify certificates without an account
+		certificateRg.GET("/:code/verify", verifyCertificateHandler)
+		certificateRg.GET("/:code/svg", renderCertificateHandler)
+	}
+
+	rg.POST(
+		route.Playlists+constant.IdParam+route.Certificate,
+		middleware.RequireRole(models.UserTypeAppUser),
+		issueCertificateHandler,
+	)
+}
+
+func issueCertificateHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	certificate, statusCode, err := certificateservice.IssueCertificate(c, parsedPlaylistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		certificate,
+	).Send(c)
+}
+
+func getUserCertificatesHandler(c *gin.Context) {
+	certificates, statusCode, err := certificateservice.GetUserCertificates(c)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		certificates,
+	).Send(c)
+}
+
+func verifyCertificateHandler(c *gin.Context) {
+	verification, statusCode, err := certificateservice.VerifyCertificate(c, c.Param("code"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		verification,
+	).Send(c)
+}
+
+func renderCertificateHandler(c *gin.Context) {
+	code := c.Param("code")
+
+	content, statusCode, err := certificateservice.RenderCertificateSVG(c, code)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "certificate-"+code+".svg"))
+	c.Data(statusCode, certificateservice.ContentTypeSVG, content)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/logger"
 	"github.com/easc01/mindo-server/pkg/utils/constant"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
 	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -155,6 +156,7 @@ func (m *RoomManager) Broadcast(roomID uuid.UUID, userID uuid.UUID, msg string)
 		UserID:         savedMsg.UserID,
 		CommunityID:    savedMsg.CommunityID,
 		Content:        savedMsg.Content.String,
+		ContentHTML:    contentutil.HTML(savedMsg.Content.String),
 		Timestamp:      savedMsg.CreatedAt.Time,
 	})
 
@@ -0,0 +1,205 @@
+package flashcardhandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	flashcardservice "github.com/easc01/mindo-server/internal/services/flashcard_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterFlashcards(rg *gin.RouterGroup) {
+	topicRg := rg.Group(route.Topics + constant.IdParam + route.Flashcards)
+	flashcardRg := rg.Group(route.Flashcards, middleware.RequireRole(models.UserTypeAdminUser))
+
+	{
+		topicRg.GET(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getTopicFlashcardsHandler,
+		)
+		topicRg.POST(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAdminUser),
+			addTopicFlashcardHandler,
+		)
+		topicRg.PUT(
+			"/study",
+			middleware.RequireRole(models.UserTypeAppUser),
+			studyTopicFlashcardsHandler,
+		)
+		topicRg.DELETE(
+			"/study",
+			middleware.RequireRole(models.UserTypeAppUser),
+			stopStudyingTopicFlashcardsHandler,
+		)
+	}
+
+	{
+		flashcardRg.PUT(constant.IdParam, updateFlashcardHandler)
+		flashcardRg.DELETE(constant.IdParam, deleteFlashcardHandler)
+	}
+}
+
+func getTopicFlashcardsHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	deck, statusCode, err := flashcardservice.GetTopicFlashcardDeck(c, topicId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		deck,
+	).Send(c)
+}
+
+func addTopicFlashcardHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.FlashcardRequest](c)
+	if !ok {
+		return
+	}
+
+	card, statusCode, err := flashcardservice.AddTopicFlashcard(c, topicId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		card,
+	).Send(c)
+}
+
+func studyTopicFlashcardsHandler(c *gin.Context) {
+	updateTopicStudy(c, flashcardservice.StudyTopicFlashcards, "flashcards added to reviews")
+}
+
+func stopStudyingTopicFlashcardsHandler(c *gin.Context) {
+	updateTopicStudy(c, flashcardservice.StopStudyingTopicFlashcards, "flashcards removed from reviews")
+}
+
+func updateTopicStudy(
+	c *gin.Context,
+	update func(c *gin.Context, topicID uuid.UUID) (int, error),
+	done string,
+) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := update(c, topicId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		done,
+	).Send(c)
+}
+
+func updateFlashcardHandler(c *gin.Context) {
+	cardId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid flashcard id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.FlashcardRequest](c)
+	if !ok {
+		return
+	}
+
+	card, statusCode, err := flashcardservice.UpdateFlashcard(c, cardId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		card,
+	).Send(c)
+}
+
+func deleteFlashcardHandler(c *gin.Context) {
+	cardId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid flashcard id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := flashcardservice.DeleteFlashcard(c, cardId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"flashcard deleted",
+	).Send(c)
+}
@@ -0,0 +1,83 @@
+package flashcardhandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	flashcardservice "github.com/easc01/mindo-server/internal/services/flashcard_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterReviews(rg *gin.RouterGroup) {
+	reviewRg := rg.Group(route.Reviews, middleware.RequireRole(models.UserTypeAppUser))
+
+	{
+		reviewRg.GET("/due", getDueFlashcardsHandler)
+		reviewRg.POST("/:cardId", reviewFlashcardHandler)
+	}
+}
+
+func getDueFlashcardsHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 100",
+			nil,
+		).Send(c)
+		return
+	}
+
+	cards, statusCode, err := flashcardservice.GetDueFlashcards(c, limit)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		cards,
+	).Send(c)
+}
+
+func reviewFlashcardHandler(c *gin.Context) {
+	cardId, err := uuid.Parse(c.Param("cardId"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid flashcard id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.ReviewFlashcardRequest](c)
+	if !ok {
+		return
+	}
+
+	review, statusCode, err := flashcardservice.ReviewFlashcard(c, cardId, *req.Grade)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		review,
+	).Send(c)
+}
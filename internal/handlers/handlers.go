@@ -10,11 +10,15 @@ import (
 
 	"github.com/easc01/mindo-server/internal/config"
 	authhandler "github.com/easc01/mindo-server/internal/handlers/auth_handler"
+	certificatehandler "github.com/easc01/mindo-server/internal/handlers/certificate_handler"
 	communityhandler "github.com/easc01/mindo-server/internal/handlers/community_handler"
+	flashcardhandler "github.com/easc01/mindo-server/internal/handlers/flashcard_handler"
 	interesthandler "github.com/easc01/mindo-server/internal/handlers/interest_handler"
+	learningpathhandler "github.com/easc01/mindo-server/internal/handlers/learning_path_handler"
 	playlisthandler "github.com/easc01/mindo-server/internal/handlers/playlist_handler"
 	quizhandler "github.com/easc01/mindo-server/internal/handlers/quiz_handler"
 	userhandler "github.com/easc01/mindo-server/internal/handlers/user_handler"
+	youtubehandler "github.com/easc01/mindo-server/internal/handlers/youtube_handler"
 	"github.com/easc01/mindo-server/pkg/logger"
 	"github.com/easc01/mindo-server/pkg/utils/route"
 	"github.com/gin-contrib/cors"
@@ -64,10 +68,20 @@ func registerRoutes(rg *gin.RouterGroup) {
 		userhandler.RegisterAdminUserRoutes(apiRg)
 		interesthandler.RegisterInterest(apiRg)
 		playlisthandler.RegisterPlaylists(apiRg)
+		playlisthandler.RegisterPlaylistReviews(apiRg)
+		playlisthandler.RegisterPlaylistPrerequisites(apiRg)
 		playlisthandler.RegisterTopic(apiRg)
+		playlisthandler.RegisterVideoBans(apiRg)
+		playlisthandler.RegisterVideoReports(apiRg)
+		playlisthandler.RegisterStudyMaterials(apiRg)
 		communityhandler.RegisterCommunity(apiRg)
 		communityhandler.RegisterMessages(apiRg)
 		quizhandler.RegisterQuiz(apiRg)
+		flashcardhandler.RegisterFlashcards(apiRg)
+		flashcardhandler.RegisterReviews(apiRg)
+		learningpathhandler.RegisterLearningPaths(apiRg)
+		certificatehandler.RegisterCertificates(apiRg)
+		youtubehandler.RegisterYoutube(apiRg)
 	}
 }
 
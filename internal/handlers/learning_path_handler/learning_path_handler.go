@@ -0,0 +1,176 @@
+package learningpathhandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	learningpathservice "github.com/easc01/mindo-server/internal/services/learning_path_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterLearningPaths(rg *gin.RouterGroup) {
+	learningPathRg := rg.Group(route.LearningPaths)
+
+	{
+		learningPathRg.GET(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getAllLearningPathsHandler,
+		)
+
+		learningPathRg.GET(
+			constant.IdParam,
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getLearningPathHandler,
+		)
+
+		learningPathRg.POST(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAdminUser),
+			createLearningPathHandler,
+		)
+
+		learningPathRg.PUT(
+			constant.IdParam+"/steps",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			replaceLearningPathStepsHandler,
+		)
+
+		learningPathRg.DELETE(
+			constant.IdParam,
+			middleware.RequireRole(models.UserTypeAdminUser),
+			deleteLearningPathHandler,
+		)
+	}
+}
+
+func getAllLearningPathsHandler(c *gin.Context) {
+	paths, statusCode, err := learningpathservice.GetAllLearningPaths(c)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		paths,
+	).Send(c)
+}
+
+func getLearningPathHandler(c *gin.Context) {
+	parsedPathId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid learning path id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	path, statusCode, err := learningpathservice.GetLearningPath(c, parsedPathId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		path,
+	).Send(c)
+}
+
+func createLearningPathHandler(c *gin.Context) {
+	req, ok := networkutil.GetRequestBody[dto.CreateLearningPathRequest](c)
+	if !ok {
+		return
+	}
+
+	path, statusCode, err := learningpathservice.CreateLearningPath(c, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		path,
+	).Send(c)
+}
+
+func replaceLearningPathStepsHandler(c *gin.Context) {
+	parsedPathId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid learning path id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.UpdateLearningPathStepsRequest](c)
+	if !ok {
+		return
+	}
+
+	path, statusCode, err := learningpathservice.ReplaceLearningPathSteps(c, parsedPathId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		path,
+	).Send(c)
+}
+
+func deleteLearningPathHandler(c *gin.Context) {
+	parsedPathId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid learning path id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := learningpathservice.DeleteLearningPath(c, parsedPathId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"learning path deleted",
+	).Send(c)
+}
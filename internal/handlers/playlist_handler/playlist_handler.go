@@ -1,7 +1,9 @@
 package playlisthandler
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
@@ -35,17 +37,41 @@ func RegisterPlaylists(rg *gin.RouterGroup) {
 			getAllPlaylistPreviews,
 		)
 
+		playlistRg.GET(
+			"/trending",
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getTrendingPlaylistsHandler,
+		)
+
 		playlistRg.GET(
 			constant.IdParam,
 			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
 			getPlaylistByIdHandler,
 		)
 
+		playlistRg.GET(
+			constant.IdParam+"/similar",
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getSimilarPlaylistsHandler,
+		)
+
 		playlistRg.POST(
 			"/gen-ai",
 			middleware.RequireRole(models.UserTypeAppUser),
 			generatePlaylistHandler,
 		)
+
+		playlistRg.POST(
+			"/import",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			importPlaylistHandler,
+		)
+
+		playlistRg.GET(
+			constant.IdParam+"/export",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			exportPlaylistHandler,
+		)
 	}
 }
 
@@ -111,8 +137,9 @@ func createPlaylistHandler(c *gin.Context) {
 
 func getAllPlaylistPreviews(c *gin.Context) {
 	searchTag := c.Query("searchTag")
+	sortBy := c.Query("sortBy")
 
-	playlists, statusCode, err := playlistservice.GetAllPlaylistPreviews(c, searchTag)
+	playlists, statusCode, err := playlistservice.GetAllPlaylistPreviews(c, searchTag, sortBy)
 	if err != nil {
 		logger.Log.Error("failed to get playlist previews")
 		networkutil.NewErrorResponse(
@@ -129,6 +156,33 @@ func getAllPlaylistPreviews(c *gin.Context) {
 	).Send(c)
 }
 
+func getTrendingPlaylistsHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 100",
+			nil,
+		).Send(c)
+		return
+	}
+
+	playlists, statusCode, err := playlistservice.GetTrendingPlaylists(c, limit)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		playlists,
+	).Send(c)
+}
+
 func getPlaylistByIdHandler(c *gin.Context) {
 	playlistId := c.Param("id")
 
@@ -158,6 +212,45 @@ func getPlaylistByIdHandler(c *gin.Context) {
 	).Send(c)
 }
 
+func getSimilarPlaylistsHandler(c *gin.Context) {
+	playlistId := c.Param("id")
+
+	parsedPlaylistId, err := uuid.Parse(playlistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 20 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 20",
+			nil,
+		).Send(c)
+		return
+	}
+
+	playlists, statusCode, err := playlistservice.GetSimilarPlaylists(c, parsedPlaylistId, limit)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		playlists,
+	).Send(c)
+}
+
 func generatePlaylistHandler(c *gin.Context) {
 	playlistTitle := c.Query("playlistTitle")
 
@@ -186,3 +279,92 @@ func generatePlaylistHandler(c *gin.Context) {
 		playlistData,
 	).Send(c)
 }
+
+func importPlaylistHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", playlistservice.FormatJSON)
+
+	body, err := c.GetRawData()
+	if err != nil || len(body) == 0 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			message.InvalidRequestBody,
+			nil,
+		).Send(c)
+		return
+	}
+
+	bundle, importErrs := playlistservice.ParsePlaylistImport(format, body)
+	if len(importErrs) > 0 {
+		networkutil.NewErrorResponse(
+			http.StatusUnprocessableEntity,
+			"playlist import has validation errors",
+			importErrs,
+		).Send(c)
+		return
+	}
+
+	user, ok := middleware.GetUser(c)
+	if user.AdminUser == nil || !ok {
+		logger.Log.Errorf(message.NullAdminUserContext)
+		networkutil.NewErrorResponse(
+			http.StatusInternalServerError,
+			message.SomethingWentWrong,
+			message.NullAdminUserContext,
+		).Send(c)
+		return
+	}
+
+	playlistDetails, statusCode, err := playlistservice.ProcessPlaylistImport(
+		c,
+		bundle,
+		user.AdminUser.UserID,
+	)
+
+	if err != nil {
+		logger.Log.Errorf("failed to import playlist by admin, %s", user.AdminUser.UserID)
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		http.StatusCreated,
+		playlistDetails,
+	).Send(c)
+}
+
+func exportPlaylistHandler(c *gin.Context) {
+	playlistId := c.Param("id")
+	format := c.DefaultQuery("format", playlistservice.FormatJSON)
+
+	parsedPlaylistId, err := uuid.Parse(playlistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	content, fileName, contentType, statusCode, err := playlistservice.ExportPlaylist(
+		c,
+		parsedPlaylistId,
+		format,
+	)
+
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(statusCode, contentType, content)
+}
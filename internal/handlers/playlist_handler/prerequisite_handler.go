@@ -0,0 +1,143 @@
+package playlisthandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterPlaylistPrerequisites(rg *gin.RouterGroup) {
+	prerequisiteRg := rg.Group(route.Playlists + constant.IdParam + route.Prerequisites)
+
+	{
+		prerequisiteRg.GET(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getPlaylistPrerequisitesHandler,
+		)
+
+		prerequisiteRg.POST(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAdminUser),
+			addPlaylistPrerequisiteHandler,
+		)
+
+		prerequisiteRg.DELETE(
+			"/:prerequisiteId",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			removePlaylistPrerequisiteHandler,
+		)
+	}
+}
+
+func getPlaylistPrerequisitesHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	prerequisites, statusCode, err := playlistservice.GetPlaylistPrerequisites(c, parsedPlaylistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		prerequisites,
+	).Send(c)
+}
+
+func addPlaylistPrerequisiteHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.AddPlaylistPrerequisiteRequest](c)
+	if !ok {
+		return
+	}
+
+	prerequisites, statusCode, err := playlistservice.AddPlaylistPrerequisite(
+		c,
+		parsedPlaylistId,
+		req.PrerequisiteID,
+	)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		prerequisites,
+	).Send(c)
+}
+
+func removePlaylistPrerequisiteHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	parsedPrerequisiteId, err := uuid.Parse(c.Param("prerequisiteId"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid prerequisite id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := playlistservice.RemovePlaylistPrerequisite(
+		c,
+		parsedPlaylistId,
+		parsedPrerequisiteId,
+	)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"prerequisite removed",
+	).Send(c)
+}
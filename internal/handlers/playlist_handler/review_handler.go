@@ -0,0 +1,135 @@
+package playlisthandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterPlaylistReviews(rg *gin.RouterGroup) {
+	reviewRg := rg.Group(route.Playlists + constant.IdParam + route.Reviews)
+
+	{
+		reviewRg.GET(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getPlaylistReviewsHandler,
+		)
+
+		reviewRg.PUT(
+			constant.Blank,
+			middleware.RequireRole(models.UserTypeAppUser),
+			upsertPlaylistReviewHandler,
+		)
+
+		reviewRg.DELETE(
+			"/:reviewId",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			deletePlaylistReviewHandler,
+		)
+	}
+}
+
+func upsertPlaylistReviewHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.UpsertPlaylistReviewRequest](c)
+	if !ok {
+		return
+	}
+
+	review, statusCode, err := playlistservice.UpsertPlaylistReview(c, parsedPlaylistId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		review,
+	).Send(c)
+}
+
+func getPlaylistReviewsHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	reviews, statusCode, err := playlistservice.GetPlaylistReviews(c, parsedPlaylistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		reviews,
+	).Send(c)
+}
+
+func deletePlaylistReviewHandler(c *gin.Context) {
+	parsedPlaylistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	parsedReviewId, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid review id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := playlistservice.DeletePlaylistReview(c, parsedPlaylistId, parsedReviewId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"review deleted",
+	).Send(c)
+}
@@ -0,0 +1,141 @@
+package playlisthandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterStudyMaterials(rg *gin.RouterGroup) {
+	materialRg := rg.Group(route.StudyMaterials, middleware.RequireRole(models.UserTypeAppUser))
+
+	{
+		materialRg.GET(route.Bookmarks, getBookmarkedMaterialsHandler)
+	}
+}
+
+func getBookmarkedMaterialsHandler(c *gin.Context) {
+	bookmarks, statusCode, err := playlistservice.GetBookmarkedStudyMaterials(c)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		bookmarks,
+	).Send(c)
+}
+
+func getTopicMaterialHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	material, statusCode, err := playlistservice.GetStudyMaterialByTopicId(c, topicId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		material,
+	).Send(c)
+}
+
+func upsertTopicMaterialHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.UpsertStudyMaterialRequest](c)
+	if !ok {
+		return
+	}
+
+	material, statusCode, err := playlistservice.UpsertStudyMaterial(c, topicId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		material,
+	).Send(c)
+}
+
+func deleteTopicMaterialHandler(c *gin.Context) {
+	updateTopicMaterial(c, playlistservice.DeleteStudyMaterial, "study material deleted")
+}
+
+func bookmarkTopicMaterialHandler(c *gin.Context) {
+	updateTopicMaterial(c, playlistservice.BookmarkStudyMaterial, "study material bookmarked")
+}
+
+func removeTopicMaterialBookmarkHandler(c *gin.Context) {
+	updateTopicMaterial(c, playlistservice.RemoveStudyMaterialBookmark, "study material bookmark removed")
+}
+
+func updateTopicMaterial(
+	c *gin.Context,
+	update func(c *gin.Context, topicID uuid.UUID) (int, error),
+	done string,
+) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := update(c, topicId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		done,
+	).Send(c)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
 	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/utils/constant"
 	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
 	"github.com/easc01/mindo-server/pkg/utils/route"
@@ -22,6 +23,56 @@ func RegisterTopic(rg *gin.RouterGroup) {
 			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
 			getTopicVideosHandler,
 		)
+		topicRg.POST(
+			constant.IdParam+"/videos",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			addTopicVideoHandler,
+		)
+		topicRg.PUT(
+			constant.IdParam+"/videos/:videoId/pin",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			pinTopicVideoHandler,
+		)
+		topicRg.DELETE(
+			constant.IdParam+"/videos/:videoId/pin",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			unpinTopicVideoHandler,
+		)
+		topicRg.POST(
+			constant.IdParam+"/videos/:videoId/report",
+			middleware.RequireRole(models.UserTypeAppUser),
+			reportTopicVideoHandler,
+		)
+		topicRg.POST(
+			constant.IdParam+"/videos/:videoId/progress",
+			middleware.RequireRole(models.UserTypeAppUser),
+			recordTopicVideoProgressHandler,
+		)
+		topicRg.GET(
+			constant.IdParam+"/material",
+			middleware.RequireRole(models.UserTypeAppUser, models.UserTypeAdminUser),
+			getTopicMaterialHandler,
+		)
+		topicRg.PUT(
+			constant.IdParam+"/material",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			upsertTopicMaterialHandler,
+		)
+		topicRg.DELETE(
+			constant.IdParam+"/material",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			deleteTopicMaterialHandler,
+		)
+		topicRg.PUT(
+			constant.IdParam+"/material/bookmark",
+			middleware.RequireRole(models.UserTypeAppUser),
+			bookmarkTopicMaterialHandler,
+		)
+		topicRg.DELETE(
+			constant.IdParam+"/material/bookmark",
+			middleware.RequireRole(models.UserTypeAppUser),
+			removeTopicMaterialBookmarkHandler,
+		)
 	}
 }
 
@@ -54,3 +105,147 @@ func getTopicVideosHandler(c *gin.Context) {
 		videos,
 	).Send(c)
 }
+
+func addTopicVideoHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.AddTopicVideoRequest](c)
+	if !ok {
+		return
+	}
+
+	video, statusCode, err := playlistservice.AddTopicVideo(c, topicId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		video,
+	).Send(c)
+}
+
+func reportTopicVideoHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.ReportVideoRequest](c)
+	if !ok {
+		return
+	}
+
+	report, statusCode, err := playlistservice.ReportVideo(c, topicId, c.Param("videoId"), req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		report,
+	).Send(c)
+}
+
+func recordTopicVideoProgressHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.VideoProgressRequest](c)
+	if !ok {
+		return
+	}
+
+	progress, statusCode, err := playlistservice.RecordVideoProgress(c, topicId, c.Param("videoId"), req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		progress,
+	).Send(c)
+}
+
+func pinTopicVideoHandler(c *gin.Context) {
+	setTopicVideoPin(c, playlistservice.PinTopicVideo)
+}
+
+func unpinTopicVideoHandler(c *gin.Context) {
+	setTopicVideoPin(c, playlistservice.UnpinTopicVideo)
+}
+
+func setTopicVideoPin(
+	c *gin.Context,
+	update func(c *gin.Context, topicID uuid.UUID, videoID string) (int, error),
+) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := update(c, topicId, c.Param("videoId"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	videos, statusCode, err := playlistservice.GetVideosByTopicId(c, topicId, constant.Blank)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		http.StatusAccepted,
+		videos,
+	).Send(c)
+}
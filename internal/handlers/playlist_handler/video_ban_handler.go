@@ -0,0 +1,91 @@
+package playlisthandler
+
+import (
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterVideoBans(rg *gin.RouterGroup) {
+	banRg := rg.Group(route.VideoBans, middleware.RequireRole(models.UserTypeAdminUser))
+
+	{
+		banRg.GET(constant.Blank, getVideoBansHandler)
+		banRg.POST(constant.Blank, createVideoBanHandler)
+		banRg.DELETE(constant.IdParam, deleteVideoBanHandler)
+	}
+}
+
+func getVideoBansHandler(c *gin.Context) {
+	bans, statusCode, err := playlistservice.GetVideoBans(c)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		bans,
+	).Send(c)
+}
+
+func createVideoBanHandler(c *gin.Context) {
+	req, ok := networkutil.GetRequestBody[dto.CreateVideoBanRequest](c)
+	if !ok {
+		return
+	}
+
+	ban, statusCode, err := playlistservice.BanVideo(c, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		ban,
+	).Send(c)
+}
+
+func deleteVideoBanHandler(c *gin.Context) {
+	banId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid video ban id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	statusCode, err := playlistservice.UnbanVideo(c, banId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"video ban removed",
+	).Send(c)
+}
@@ -0,0 +1,94 @@
+package playlisthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterVideoReports(rg *gin.RouterGroup) {
+	reportRg := rg.Group(route.VideoReports, middleware.RequireRole(models.UserTypeAdminUser))
+
+	{
+		reportRg.GET(constant.Blank, getVideoReportQueueHandler)
+		reportRg.POST("/:topicId/:videoId/resolve", resolveVideoReportsHandler)
+	}
+}
+
+func getVideoReportQueueHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 100",
+			nil,
+		).Send(c)
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"offset must not be negative",
+			nil,
+		).Send(c)
+		return
+	}
+
+	queue, statusCode, err := playlistservice.GetVideoReportQueue(c, limit, offset)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		queue,
+	).Send(c)
+}
+
+func resolveVideoReportsHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("topicId"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.ResolveVideoReportRequest](c)
+	if !ok {
+		return
+	}
+
+	statusCode, err := playlistservice.ResolveVideoReports(c, topicId, c.Param("videoId"), req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		"video reports resolved",
+	).Send(c)
+}
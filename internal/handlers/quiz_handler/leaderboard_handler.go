@@ -0,0 +1,125 @@
+package quizhandler
+
+import (
+	"net/http"
+	"strconv"
+
+	quizservice "github.com/easc01/mindo-server/internal/services/quiz_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func getQuizLeaderboardHandler(c *gin.Context) {
+	quizId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	limit, offset, ok := getLeaderboardPage(c)
+	if !ok {
+		return
+	}
+
+	leaderboard, statusCode, err := quizservice.GetQuizLeaderboard(c, quizId, limit, offset)
+	sendLeaderboard(c, leaderboard, statusCode, err)
+}
+
+func getPlaylistLeaderboardHandler(c *gin.Context) {
+	playlistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	limit, offset, ok := getLeaderboardPage(c)
+	if !ok {
+		return
+	}
+
+	leaderboard, statusCode, err := quizservice.GetPlaylistLeaderboard(
+		c,
+		playlistId,
+		c.DefaultQuery("period", quizservice.LeaderboardPeriodAllTime),
+		limit,
+		offset,
+	)
+	sendLeaderboard(c, leaderboard, statusCode, err)
+}
+
+func getCommunityLeaderboardHandler(c *gin.Context) {
+	communityId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid community id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	limit, offset, ok := getLeaderboardPage(c)
+	if !ok {
+		return
+	}
+
+	leaderboard, statusCode, err := quizservice.GetCommunityLeaderboard(
+		c,
+		communityId,
+		c.DefaultQuery("period", quizservice.LeaderboardPeriodAllTime),
+		limit,
+		offset,
+	)
+	sendLeaderboard(c, leaderboard, statusCode, err)
+}
+
+// getLeaderboardPage reads limit and offset, a bad value is answered here
+func getLeaderboardPage(c *gin.Context) (int, int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 100",
+			nil,
+		).Send(c)
+		return 0, 0, false
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"offset must not be negative",
+			nil,
+		).Send(c)
+		return 0, 0, false
+	}
+
+	return limit, offset, true
+}
+
+func sendLeaderboard(c *gin.Context, leaderboard dto.LeaderboardDTO, statusCode int, err error) {
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		leaderboard,
+	).Send(c)
+}
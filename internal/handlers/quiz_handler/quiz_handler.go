@@ -2,18 +2,24 @@ package quizhandler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
 	quizservice "github.com/easc01/mindo-server/internal/services/quiz_service"
 	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
 	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
 	"github.com/easc01/mindo-server/pkg/utils/route"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func RegisterQuiz(rg *gin.RouterGroup) {
 	quizRg := rg.Group(route.Quizzes)
+	topicRg := rg.Group(route.Topics)
+	playlistRg := rg.Group(route.Playlists)
+	communityRg := rg.Group(route.Communities)
 
 	{
 		quizRg.POST(
@@ -27,6 +33,86 @@ func RegisterQuiz(rg *gin.RouterGroup) {
 			middleware.RequireRole(models.UserTypeAppUser),
 			verifyQuizAnswersHandler,
 		)
+
+		quizRg.GET(
+			"/attempts",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getQuizAttemptsHandler,
+		)
+
+		quizRg.GET(
+			"/attempts"+constant.IdParam,
+			middleware.RequireRole(models.UserTypeAppUser),
+			getQuizAttemptHandler,
+		)
+
+		quizRg.PUT(
+			constant.IdParam+"/scoring",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			updateQuizScoringHandler,
+		)
+
+		quizRg.PUT(
+			constant.IdParam+"/time-limit",
+			middleware.RequireRole(models.UserTypeAdminUser),
+			updateQuizTimeLimitHandler,
+		)
+
+		quizRg.POST(
+			constant.IdParam+"/start",
+			middleware.RequireRole(models.UserTypeAppUser),
+			startQuizHandler,
+		)
+
+		quizRg.POST(
+			constant.IdParam+"/retake",
+			middleware.RequireRole(models.UserTypeAppUser),
+			startQuizHandler,
+		)
+
+		quizRg.GET(
+			"/sessions"+constant.IdParam,
+			middleware.RequireRole(models.UserTypeAppUser),
+			getQuizSessionHandler,
+		)
+
+		quizRg.PUT(
+			"/sessions"+constant.IdParam+"/answers",
+			middleware.RequireRole(models.UserTypeAppUser),
+			saveQuizAnswersHandler,
+		)
+
+		quizRg.GET(
+			constant.IdParam+"/leaderboard",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getQuizLeaderboardHandler,
+		)
+	}
+
+	{
+		topicRg.GET(
+			constant.IdParam+"/quiz",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getTopicQuizHandler,
+		)
+
+		playlistRg.GET(
+			constant.IdParam+"/exam",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getPlaylistExamHandler,
+		)
+
+		playlistRg.GET(
+			constant.IdParam+"/leaderboard",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getPlaylistLeaderboardHandler,
+		)
+
+		communityRg.GET(
+			constant.IdParam+"/leaderboard",
+			middleware.RequireRole(models.UserTypeAppUser),
+			getCommunityLeaderboardHandler,
+		)
 	}
 }
 
@@ -44,7 +130,8 @@ func generateQuizHandler(c *gin.Context) {
 
 	quizData, err := quizservice.GenerateAndSaveQuiz(c, dto.GenerateQuizParams{
 		TopicName:     topicName,
-		QuestionCount: 10,
+		QuestionCount: quizservice.QuizQuestionCount,
+		QuestionTypes: quizservice.QuestionTypes,
 	})
 
 	if err != nil {
@@ -68,11 +155,11 @@ func verifyQuizAnswersHandler(c *gin.Context) {
 		return
 	}
 
-	quizData, err := quizservice.VerifyQuizResults(c, req)
+	quizData, statusCode, err := quizservice.VerifyQuizResults(c, req)
 
 	if err != nil {
 		networkutil.NewErrorResponse(
-			http.StatusInternalServerError,
+			statusCode,
 			err.Error(),
 			nil,
 		).Send(c)
@@ -80,7 +167,275 @@ func verifyQuizAnswersHandler(c *gin.Context) {
 	}
 
 	networkutil.NewResponse(
-		http.StatusAccepted,
+		statusCode,
 		quizData,
 	).Send(c)
 }
+
+func getQuizAttemptsHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"limit must be between 1 and 100",
+			nil,
+		).Send(c)
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"offset must not be negative",
+			nil,
+		).Send(c)
+		return
+	}
+
+	attempts, statusCode, err := quizservice.GetQuizAttempts(c, limit, offset)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		attempts,
+	).Send(c)
+}
+
+func getQuizAttemptHandler(c *gin.Context) {
+	attemptId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz attempt id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	attempt, statusCode, err := quizservice.GetQuizAttemptById(c, attemptId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		attempt,
+	).Send(c)
+}
+
+func updateQuizScoringHandler(c *gin.Context) {
+	quizId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.UpdateQuizScoringRequest](c)
+	if !ok {
+		return
+	}
+
+	scoring, statusCode, err := quizservice.UpdateQuizScoring(c, quizId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		scoring,
+	).Send(c)
+}
+
+func startQuizHandler(c *gin.Context) {
+	quizId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	quizData, statusCode, err := quizservice.StartQuiz(c, quizId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		quizData,
+	).Send(c)
+}
+
+func getTopicQuizHandler(c *gin.Context) {
+	topicId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid topic id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	quizData, statusCode, err := quizservice.GetTopicQuiz(c, topicId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		quizData,
+	).Send(c)
+}
+
+func getPlaylistExamHandler(c *gin.Context) {
+	playlistId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid playlist id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	quizData, statusCode, err := quizservice.GetPlaylistExam(c, playlistId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		quizData,
+	).Send(c)
+}
+
+func getQuizSessionHandler(c *gin.Context) {
+	sessionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz session id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	quizData, statusCode, err := quizservice.GetQuizSession(c, sessionId)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		quizData,
+	).Send(c)
+}
+
+func saveQuizAnswersHandler(c *gin.Context) {
+	sessionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz session id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.SaveQuizAnswersRequest](c)
+	if !ok {
+		return
+	}
+
+	draft, statusCode, err := quizservice.SaveQuizAnswers(c, sessionId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		draft,
+	).Send(c)
+}
+
+func updateQuizTimeLimitHandler(c *gin.Context) {
+	quizId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"invalid quiz id",
+			err.Error(),
+		).Send(c)
+		return
+	}
+
+	req, ok := networkutil.GetRequestBody[dto.UpdateQuizTimeLimitRequest](c)
+	if !ok {
+		return
+	}
+
+	timeLimit, statusCode, err := quizservice.UpdateQuizTimeLimit(c, quizId, req)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		timeLimit,
+	).Send(c)
+}
@@ -0,0 +1,48 @@
+package youtubehandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	youtubeservice "github.com/easc01/mindo-server/internal/services/youtube_service"
+	networkutil "github.com/easc01/mindo-server/pkg/utils/network_util"
+	"github.com/easc01/mindo-server/pkg/utils/route"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterYoutube(rg *gin.RouterGroup) {
+	youtubeRg := rg.Group(route.Youtube, middleware.RequireRole(models.UserTypeAdminUser))
+
+	{
+		youtubeRg.GET(route.Quota, getQuotaUsageHandler)
+	}
+}
+
+func getQuotaUsageHandler(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		networkutil.NewErrorResponse(
+			http.StatusBadRequest,
+			"days must be between 1 and 90",
+			nil,
+		).Send(c)
+		return
+	}
+
+	usage, statusCode, err := youtubeservice.GetQuotaUsage(c, days)
+	if err != nil {
+		networkutil.NewErrorResponse(
+			statusCode,
+			err.Error(),
+			nil,
+		).Send(c)
+		return
+	}
+
+	networkutil.NewResponse(
+		statusCode,
+		usage,
+	).Send(c)
+}
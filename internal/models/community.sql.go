@@ -133,3 +133,25 @@ func (q *Queries) CreateNewUserJoinedCommunityById(ctx context.Context, arg Crea
 	_, err := q.db.ExecContext(ctx, createNewUserJoinedCommunityById, arg.UserID, arg.CommunityID, arg.UpdatedBy)
 	return err
 }
+
+const getCommunityById = `-- name: GetCommunityById :one
+SELECT id, title, about, thumbnail_url, logo_url, updated_at, created_at, updated_by
+FROM community
+WHERE id = $1
+`
+
+func (q *Queries) GetCommunityById(ctx context.Context, id uuid.UUID) (Community, error) {
+	row := q.db.QueryRowContext(ctx, getCommunityById, id)
+	var i Community
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.About,
+		&i.ThumbnailUrl,
+		&i.LogoUrl,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
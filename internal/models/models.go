@@ -7,6 +7,7 @@ package models
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -62,6 +63,52 @@ func (ns NullColor) Value() (driver.Value, error) {
 	return string(ns.Color), nil
 }
 
+type QuizQuestionType string
+
+const (
+	QuizQuestionTypeSingleChoice QuizQuestionType = "single_choice"
+	QuizQuestionTypeMultiSelect  QuizQuestionType = "multi_select"
+	QuizQuestionTypeTrueFalse    QuizQuestionType = "true_false"
+	QuizQuestionTypeNumeric      QuizQuestionType = "numeric"
+	QuizQuestionTypeShortText    QuizQuestionType = "short_text"
+	QuizQuestionTypeOrdering     QuizQuestionType = "ordering"
+)
+
+func (e *QuizQuestionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = QuizQuestionType(s)
+	case string:
+		*e = QuizQuestionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for QuizQuestionType: %T", src)
+	}
+	return nil
+}
+
+type NullQuizQuestionType struct {
+	QuizQuestionType QuizQuestionType
+	Valid            bool // Valid is true if QuizQuestionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullQuizQuestionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.QuizQuestionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.QuizQuestionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullQuizQuestionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.QuizQuestionType), nil
+}
+
 type UserType string
 
 const (
@@ -141,6 +188,20 @@ type AppUserInterest struct {
 	UpdatedBy  uuid.NullUUID
 }
 
+type Certificate struct {
+	ID           uuid.UUID
+	Code         string
+	UserID       uuid.UUID
+	PlaylistID   uuid.UUID
+	LearnerName  string
+	PlaylistName string
+	IssuedAt     time.Time
+	Signature    string
+	UpdatedAt    sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedBy    uuid.NullUUID
+}
+
 type Community struct {
 	ID           uuid.UUID
 	Title        sql.NullString
@@ -152,6 +213,27 @@ type Community struct {
 	UpdatedBy    uuid.NullUUID
 }
 
+type Flashcard struct {
+	ID             uuid.UUID
+	DeckID         uuid.UUID
+	Front          string
+	Back           string
+	QuizQuestionID uuid.NullUUID
+	UpdatedAt      sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedBy      uuid.NullUUID
+}
+
+type FlashcardDeck struct {
+	ID        uuid.UUID
+	TopicID   uuid.NullUUID
+	QuizID    uuid.NullUUID
+	Name      string
+	UpdatedAt sql.NullTime
+	CreatedAt sql.NullTime
+	UpdatedBy uuid.NullUUID
+}
+
 type Interest struct {
 	ID        uuid.UUID
 	Name      sql.NullString
@@ -160,6 +242,25 @@ type Interest struct {
 	UpdatedBy uuid.NullUUID
 }
 
+type LearningPath struct {
+	ID           uuid.UUID
+	Name         string
+	Description  sql.NullString
+	ThumbnailUrl sql.NullString
+	UpdatedAt    sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedBy    uuid.NullUUID
+}
+
+type LearningPathStep struct {
+	LearningPathID uuid.UUID
+	PlaylistID     uuid.UUID
+	StepNumber     int32
+	UpdatedAt      sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedBy      uuid.NullUUID
+}
+
 type Message struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
@@ -171,48 +272,133 @@ type Message struct {
 }
 
 type Playlist struct {
-	ID           uuid.UUID
-	InterestID   uuid.NullUUID
-	Name         sql.NullString
-	Code         string
-	Description  sql.NullString
-	Views        sql.NullInt32
-	IsAiGen      bool
-	ThumbnailUrl sql.NullString
-	UpdatedAt    sql.NullTime
-	CreatedAt    sql.NullTime
-	UpdatedBy    uuid.NullUUID
+	ID            uuid.UUID
+	InterestID    uuid.NullUUID
+	Name          sql.NullString
+	Code          string
+	Description   sql.NullString
+	Views         sql.NullInt32
+	IsAiGen       bool
+	ThumbnailUrl  sql.NullString
+	AverageRating float64
+	RatingCount   int32
+	UpdatedAt     sql.NullTime
+	CreatedAt     sql.NullTime
+	UpdatedBy     uuid.NullUUID
+}
+
+type PlaylistLeaderboardStat struct {
+	PlaylistID        uuid.UUID
+	Period            string
+	UserID            uuid.UUID
+	TotalScore        float64
+	QuizzesCompleted  int32
+	CompletionSeconds int64
+	Rank              int32
+	UpdatedAt         sql.NullTime
+}
+
+type PlaylistPrerequisite struct {
+	PlaylistID             uuid.UUID
+	PrerequisitePlaylistID uuid.UUID
+	UpdatedAt              sql.NullTime
+	CreatedAt              sql.NullTime
+	UpdatedBy              uuid.NullUUID
+}
+
+type PlaylistReview struct {
+	ID         uuid.UUID
+	PlaylistID uuid.UUID
+	UserID     uuid.UUID
+	Rating     int32
+	Review     sql.NullString
+	UpdatedAt  sql.NullTime
+	CreatedAt  sql.NullTime
+	UpdatedBy  uuid.NullUUID
+}
+
+type PlaylistSimilarity struct {
+	PlaylistID        uuid.UUID
+	SimilarPlaylistID uuid.UUID
+	Score             float64
+	TopicScore        float64
+	InterestScore     float64
+	CoOccurrenceScore float64
+	UpdatedAt         sql.NullTime
+}
+
+type PlaylistView struct {
+	ID          uuid.UUID
+	PlaylistID  uuid.UUID
+	UserID      uuid.UUID
+	WindowStart time.Time
+	ViewedAt    time.Time
+}
+
+type PlaylistViewStat struct {
+	PlaylistID    uuid.UUID
+	LegacyViews   int32
+	Views24h      int32
+	Views7d       int32
+	ViewsAllTime  int32
+	ViewsRolledUp int32
+	TrendingScore float64
+	UpdatedAt     sql.NullTime
 }
 
 type Quiz struct {
-	ID           uuid.UUID
-	Name         sql.NullString
-	ThumbnailUrl sql.NullString
-	PlayCount    sql.NullInt32
-	UpdatedAt    sql.NullTime
-	CreatedAt    sql.NullTime
-	UpdatedBy    uuid.NullUUID
+	ID               uuid.UUID
+	Name             sql.NullString
+	ThumbnailUrl     sql.NullString
+	PlayCount        sql.NullInt32
+	TopicID          uuid.NullUUID
+	PlaylistID       uuid.NullUUID
+	TimeLimitSeconds sql.NullInt32
+	NegativeMarking  float64
+	GradeBands       json.RawMessage
+	UpdatedAt        sql.NullTime
+	CreatedAt        sql.NullTime
+	UpdatedBy        uuid.NullUUID
+}
+
+type QuizLeaderboardEntry struct {
+	QuizID            uuid.UUID
+	UserID            uuid.UUID
+	QuizResultID      uuid.UUID
+	PlaylistID        uuid.NullUUID
+	Score             float64
+	CompletionSeconds int32
+	SubmittedAt       time.Time
+	CreatedAt         sql.NullTime
 }
 
 type QuizQuestion struct {
-	ID            uuid.UUID
-	QuizID        uuid.UUID
-	Question      sql.NullString
-	Options       []string
-	CorrectOption sql.NullInt32
-	UpdatedAt     sql.NullTime
-	CreatedAt     sql.NullTime
-	UpdatedBy     uuid.NullUUID
+	ID             uuid.UUID
+	QuizID         uuid.UUID
+	QuestionType   QuizQuestionType
+	Question       sql.NullString
+	Options        []string
+	CorrectOption  sql.NullInt32
+	Answer         json.RawMessage
+	Explanation    sql.NullString
+	ReferenceLinks json.RawMessage
+	Weight         float64
+	UpdatedAt      sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedBy      uuid.NullUUID
 }
 
 type QuizResult struct {
-	ID        uuid.UUID
-	QuizID    uuid.UUID
-	UserID    uuid.UUID
-	Score     sql.NullString
-	UpdatedAt sql.NullTime
-	CreatedAt sql.NullTime
-	UpdatedBy uuid.NullUUID
+	ID             uuid.UUID
+	QuizID         uuid.UUID
+	UserID         uuid.UUID
+	Score          sql.NullString
+	Grade          sql.NullString
+	CorrectAnswers int32
+	TotalQuestions int32
+	UpdatedAt      sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedBy      uuid.NullUUID
 }
 
 type QuizResultQuestion struct {
@@ -220,19 +406,39 @@ type QuizResultQuestion struct {
 	QuizResultID    uuid.UUID
 	QuizQuestionID  uuid.UUID
 	AttemptedOption sql.NullInt32
+	AttemptedAnswer json.RawMessage
+	IsCorrect       bool
 	UpdatedAt       sql.NullTime
 	CreatedAt       sql.NullTime
 	UpdatedBy       uuid.NullUUID
 }
 
+type QuizSession struct {
+	ID           uuid.UUID
+	QuizID       uuid.UUID
+	UserID       uuid.UUID
+	QuestionIds  []uuid.UUID
+	OptionOrders json.RawMessage
+	DraftAnswers json.RawMessage
+	StartedAt    time.Time
+	DeadlineAt   sql.NullTime
+	SubmittedAt  sql.NullTime
+	QuizResultID uuid.NullUUID
+	UpdatedAt    sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedBy    uuid.NullUUID
+}
+
 type StudyMaterial struct {
-	ID        uuid.UUID
-	TopicID   uuid.UUID
-	Title     sql.NullString
-	Content   sql.NullString
-	UpdatedAt sql.NullTime
-	CreatedAt sql.NullTime
-	UpdatedBy uuid.NullUUID
+	ID          uuid.UUID
+	TopicID     uuid.UUID
+	Title       sql.NullString
+	Content     sql.NullString
+	ContentText sql.NullString
+	IsGenerated bool
+	UpdatedAt   sql.NullTime
+	CreatedAt   sql.NullTime
+	UpdatedBy   uuid.NullUUID
 }
 
 type Topic struct {
@@ -253,6 +459,29 @@ type User struct {
 	UpdatedBy uuid.NullUUID
 }
 
+type UserFlashcard struct {
+	UserID         uuid.UUID
+	FlashcardID    uuid.UUID
+	EaseFactor     float64
+	IntervalDays   int32
+	Repetitions    int32
+	Lapses         int32
+	LastGrade      sql.NullInt32
+	LastReviewedAt sql.NullTime
+	DueAt          time.Time
+	UpdatedAt      sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedBy      uuid.NullUUID
+}
+
+type UserFlashcardDeck struct {
+	UserID    uuid.UUID
+	DeckID    uuid.UUID
+	UpdatedAt sql.NullTime
+	CreatedAt sql.NullTime
+	UpdatedBy uuid.NullUUID
+}
+
 type UserJoinedCommunity struct {
 	UserID      uuid.UUID
 	CommunityID uuid.UUID
@@ -289,23 +518,71 @@ type UserToken struct {
 }
 
 type UserWatchedVideo struct {
-	UserID         uuid.UUID
-	YoutubeVideoID uuid.UUID
-	UpdatedAt      sql.NullTime
-	CreatedAt      sql.NullTime
-	UpdatedBy      uuid.NullUUID
+	UserID          uuid.UUID
+	YoutubeVideoID  uuid.UUID
+	PositionSeconds int32
+	WatchSeconds    int32
+	IsWatched       bool
+	WatchedAt       sql.NullTime
+	UpdatedAt       sql.NullTime
+	CreatedAt       sql.NullTime
+	UpdatedBy       uuid.NullUUID
+}
+
+type VideoBan struct {
+	ID        uuid.UUID
+	Kind      string
+	Value     string
+	Reason    sql.NullString
+	UpdatedAt sql.NullTime
+	CreatedAt sql.NullTime
+	UpdatedBy uuid.NullUUID
+}
+
+type VideoReport struct {
+	ID         uuid.UUID
+	TopicID    uuid.UUID
+	VideoID    string
+	UserID     uuid.UUID
+	Reason     string
+	Note       sql.NullString
+	ResolvedAt sql.NullTime
+	Resolution sql.NullString
+	UpdatedAt  sql.NullTime
+	CreatedAt  sql.NullTime
+	UpdatedBy  uuid.NullUUID
+}
+
+type YoutubeQuotaUsage struct {
+	UsageDate time.Time
+	Endpoint  string
+	Units     int32
+	Calls     int32
+	UpdatedAt sql.NullTime
+	CreatedAt sql.NullTime
 }
 
 type YoutubeVideo struct {
-	ID           uuid.UUID
-	TopicID      uuid.UUID
-	VideoID      string
-	Title        sql.NullString
-	VideoDate    sql.NullTime
-	ChannelTitle sql.NullString
-	ThumbnailUrl sql.NullString
-	ExpiryAt     sql.NullTime
-	UpdatedAt    sql.NullTime
-	CreatedAt    sql.NullTime
-	UpdatedBy    uuid.NullUUID
+	ID              uuid.UUID
+	TopicID         uuid.UUID
+	VideoID         string
+	Provider        string
+	Title           sql.NullString
+	VideoDate       sql.NullTime
+	ChannelTitle    sql.NullString
+	ChannelID       sql.NullString
+	ThumbnailUrl    sql.NullString
+	DurationSeconds sql.NullInt32
+	ViewCount       sql.NullInt64
+	LikeCount       sql.NullInt64
+	HasCaptions     bool
+	Definition      sql.NullString
+	RankScore       float64
+	ExpiryAt        sql.NullTime
+	IsPinned        bool
+	IsManual        bool
+	IsHidden        bool
+	UpdatedAt       sql.NullTime
+	CreatedAt       sql.NullTime
+	UpdatedBy       uuid.NullUUID
 }
@@ -31,7 +31,7 @@ VALUES (
         $5, -- domain/interest id
         $6, -- Updated By
         $7  -- Is gen by ai
-    ) RETURNING id, interest_id, name, code, description, views, is_ai_gen, thumbnail_url, updated_at, created_at, updated_by
+    ) RETURNING id, interest_id, name, code, description, views, is_ai_gen, thumbnail_url, average_rating, rating_count, updated_at, created_at, updated_by
 `
 
 type CreatePlaylistParams struct {
@@ -65,6 +65,8 @@ func (q *Queries) CreatePlaylist(ctx context.Context, arg CreatePlaylistParams)
 		&i.Views,
 		&i.IsAiGen,
 		&i.ThumbnailUrl,
+		&i.AverageRating,
+		&i.RatingCount,
 		&i.UpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedBy,
@@ -81,6 +83,11 @@ SELECT
     p.thumbnail_url,
     p.interest_id,
     p.views,
+    p.average_rating,
+    p.rating_count,
+    COALESCE(s.views_24h, 0)::int AS views_24h,
+    COALESCE(s.views_7d, 0)::int AS views_7d,
+    COALESCE(s.trending_score, 0)::float8 AS trending_score,
     p.created_at,
     p.updated_at,
     p.updated_by,
@@ -88,28 +95,44 @@ SELECT
     COALESCE(COUNT(t.id), 0) AS topics_count
 FROM playlist p
 LEFT JOIN topic t ON t.playlist_id = p.id
-WHERE $1 = '' OR similarity(p.name, $1) > 0.05
-GROUP BY p.id
-ORDER BY similarity(p.name, $1) DESC
+LEFT JOIN playlist_view_stat s ON s.playlist_id = p.id
+WHERE $1::text = '' OR similarity(p.name, $1::text) > 0.05
+GROUP BY p.id, s.playlist_id
+ORDER BY
+    CASE WHEN $2::text = 'trending' THEN COALESCE(s.trending_score, 0) END DESC,
+    CASE WHEN $2::text = 'rating' THEN p.average_rating END DESC,
+    CASE WHEN $2::text = 'rating' THEN p.rating_count END DESC,
+    CASE WHEN $2::text = 'ratingCount' THEN p.rating_count END DESC,
+    similarity(p.name, $1::text) DESC
 `
 
+type GetAllPlaylistsPreviewsParams struct {
+	SearchTag string
+	SortBy    string
+}
+
 type GetAllPlaylistsPreviewsRow struct {
-	ID           uuid.UUID
-	Name         sql.NullString
-	Description  sql.NullString
-	Code         string
-	ThumbnailUrl sql.NullString
-	InterestID   uuid.NullUUID
-	Views        sql.NullInt32
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
-	UpdatedBy    uuid.NullUUID
-	IsAiGen      bool
-	TopicsCount  interface{}
+	ID            uuid.UUID
+	Name          sql.NullString
+	Description   sql.NullString
+	Code          string
+	ThumbnailUrl  sql.NullString
+	InterestID    uuid.NullUUID
+	Views         sql.NullInt32
+	AverageRating float64
+	RatingCount   int32
+	Views24h      int32
+	Views7d       int32
+	TrendingScore float64
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	UpdatedBy     uuid.NullUUID
+	IsAiGen       bool
+	TopicsCount   interface{}
 }
 
-func (q *Queries) GetAllPlaylistsPreviews(ctx context.Context, dollar_1 interface{}) ([]GetAllPlaylistsPreviewsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlaylistsPreviews, dollar_1)
+func (q *Queries) GetAllPlaylistsPreviews(ctx context.Context, arg GetAllPlaylistsPreviewsParams) ([]GetAllPlaylistsPreviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlaylistsPreviews, arg.SearchTag, arg.SortBy)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +148,11 @@ func (q *Queries) GetAllPlaylistsPreviews(ctx context.Context, dollar_1 interfac
 			&i.ThumbnailUrl,
 			&i.InterestID,
 			&i.Views,
+			&i.AverageRating,
+			&i.RatingCount,
+			&i.Views24h,
+			&i.Views7d,
+			&i.TrendingScore,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UpdatedBy,
@@ -144,16 +172,51 @@ func (q *Queries) GetAllPlaylistsPreviews(ctx context.Context, dollar_1 interfac
 	return items, nil
 }
 
-const updatePlaylistViewCountById = `-- name: UpdatePlaylistViewCountById :exec
-UPDATE playlist SET views = views + $2 WHERE id = $1
+const getPlaylistById = `-- name: GetPlaylistById :one
+SELECT id, interest_id, name, code, description, views, is_ai_gen, thumbnail_url, average_rating, rating_count, updated_at, created_at, updated_by FROM playlist WHERE id = $1
 `
 
-type UpdatePlaylistViewCountByIdParams struct {
-	ID    uuid.UUID
-	Views sql.NullInt32
+func (q *Queries) GetPlaylistById(ctx context.Context, id uuid.UUID) (Playlist, error) {
+	row := q.db.QueryRowContext(ctx, getPlaylistById, id)
+	var i Playlist
+	err := row.Scan(
+		&i.ID,
+		&i.InterestID,
+		&i.Name,
+		&i.Code,
+		&i.Description,
+		&i.Views,
+		&i.IsAiGen,
+		&i.ThumbnailUrl,
+		&i.AverageRating,
+		&i.RatingCount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
 }
 
-func (q *Queries) UpdatePlaylistViewCountById(ctx context.Context, arg UpdatePlaylistViewCountByIdParams) error {
-	_, err := q.db.ExecContext(ctx, updatePlaylistViewCountById, arg.ID, arg.Views)
+const refreshPlaylistRatingById = `-- name: RefreshPlaylistRatingById :exec
+UPDATE playlist
+SET
+    average_rating = COALESCE(
+        (
+            SELECT AVG(pr.rating)
+            FROM playlist_review pr
+            WHERE pr.playlist_id = $1
+        ),
+        0
+    ),
+    rating_count = (
+        SELECT COUNT(*)
+        FROM playlist_review pr
+        WHERE pr.playlist_id = $1
+    )
+WHERE id = $1
+`
+
+func (q *Queries) RefreshPlaylistRatingById(ctx context.Context, playlistID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, refreshPlaylistRatingById, playlistID)
 	return err
 }
@@ -8,13 +8,276 @@ package models
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+const createQuizResult = `-- name: CreateQuizResult :one
+INSERT INTO
+    "quiz_result" (
+        quiz_id,
+        user_id,
+        score,
+        grade,
+        correct_answers,
+        total_questions,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $2) RETURNING id, quiz_id, user_id, score, grade, correct_answers, total_questions, updated_at, created_at, updated_by
+`
+
+type CreateQuizResultParams struct {
+	QuizID         uuid.UUID
+	UserID         uuid.UUID
+	Score          sql.NullString
+	Grade          sql.NullString
+	CorrectAnswers int32
+	TotalQuestions int32
+}
+
+func (q *Queries) CreateQuizResult(ctx context.Context, arg CreateQuizResultParams) (QuizResult, error) {
+	row := q.db.QueryRowContext(ctx, createQuizResult,
+		arg.QuizID,
+		arg.UserID,
+		arg.Score,
+		arg.Grade,
+		arg.CorrectAnswers,
+		arg.TotalQuestions,
+	)
+	var i QuizResult
+	err := row.Scan(
+		&i.ID,
+		&i.QuizID,
+		&i.UserID,
+		&i.Score,
+		&i.Grade,
+		&i.CorrectAnswers,
+		&i.TotalQuestions,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const createQuizResultQuestion = `-- name: CreateQuizResultQuestion :exec
+INSERT INTO
+    "quiz_result_question" (
+        quiz_result_id,
+        quiz_question_id,
+        attempted_option,
+        attempted_answer,
+        is_correct,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateQuizResultQuestionParams struct {
+	QuizResultID    uuid.UUID
+	QuizQuestionID  uuid.UUID
+	AttemptedOption sql.NullInt32
+	AttemptedAnswer json.RawMessage
+	IsCorrect       bool
+	UpdatedBy       uuid.NullUUID
+}
+
+func (q *Queries) CreateQuizResultQuestion(ctx context.Context, arg CreateQuizResultQuestionParams) error {
+	_, err := q.db.ExecContext(ctx, createQuizResultQuestion,
+		arg.QuizResultID,
+		arg.QuizQuestionID,
+		arg.AttemptedOption,
+		arg.AttemptedAnswer,
+		arg.IsCorrect,
+		arg.UpdatedBy,
+	)
+	return err
+}
+
+const getPlaylistExamQuestions = `-- name: GetPlaylistExamQuestions :many
+SELECT
+    id,
+    quiz_id,
+    question_type,
+    question,
+    options,
+    correct_option,
+    answer,
+    explanation,
+    reference_links,
+    weight,
+    updated_at,
+    created_at,
+    updated_by
+FROM (
+    SELECT
+        qq.*,
+        t.number AS topic_number,
+        ROW_NUMBER() OVER (PARTITION BY q.topic_id ORDER BY random()) AS pick
+    FROM quiz_question qq
+    JOIN quiz q ON q.id = qq.quiz_id
+    JOIN topic t ON t.id = q.topic_id
+    WHERE t.playlist_id = $1
+) bank
+WHERE pick <= $2
+ORDER BY topic_number, pick
+`
+
+type GetPlaylistExamQuestionsParams struct {
+	PlaylistID uuid.UUID
+	PerTopic   int64
+}
+
+// draws random questions from the bank of every topic quiz in a playlist,
+// in topic order
+func (q *Queries) GetPlaylistExamQuestions(ctx context.Context, arg GetPlaylistExamQuestionsParams) ([]QuizQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, getPlaylistExamQuestions, arg.PlaylistID, arg.PerTopic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuizQuestion
+	for rows.Next() {
+		var i QuizQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuizID,
+			&i.QuestionType,
+			&i.Question,
+			pq.Array(&i.Options),
+			&i.CorrectOption,
+			&i.Answer,
+			&i.Explanation,
+			&i.ReferenceLinks,
+			&i.Weight,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlaylistQuizBestScores = `-- name: GetPlaylistQuizBestScores :many
+SELECT
+    q.id AS quiz_id,
+    q.topic_id,
+    t.name AS topic_name,
+    MAX(qr.score)::double precision AS best_score
+FROM quiz q
+LEFT JOIN topic t ON t.id = q.topic_id
+LEFT JOIN quiz_result qr ON qr.quiz_id = q.id
+AND qr.user_id = $1::uuid
+WHERE
+    q.playlist_id = $2::uuid
+    OR (
+        t.playlist_id = $2::uuid
+        AND EXISTS (
+            SELECT 1
+            FROM quiz_question qq
+            WHERE qq.quiz_id = q.id
+        )
+    )
+GROUP BY q.id, q.topic_id, t.name, t.number
+ORDER BY t.number NULLS LAST, q.id
+`
+
+type GetPlaylistQuizBestScoresParams struct {
+	UserID     uuid.UUID
+	PlaylistID uuid.UUID
+}
+
+type GetPlaylistQuizBestScoresRow struct {
+	QuizID    uuid.UUID
+	TopicID   uuid.NullUUID
+	TopicName sql.NullString
+	BestScore sql.NullFloat64
+}
+
+// best score of a learner on the playlist exam and on every topic quiz of the
+// playlist with questions, quizzes never taken have no best score
+func (q *Queries) GetPlaylistQuizBestScores(ctx context.Context, arg GetPlaylistQuizBestScoresParams) ([]GetPlaylistQuizBestScoresRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlaylistQuizBestScores, arg.UserID, arg.PlaylistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlaylistQuizBestScoresRow
+	for rows.Next() {
+		var i GetPlaylistQuizBestScoresRow
+		if err := rows.Scan(
+			&i.QuizID,
+			&i.TopicID,
+			&i.TopicName,
+			&i.BestScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuestionsByIds = `-- name: GetQuestionsByIds :many
+SELECT id, quiz_id, question_type, question, options, correct_option, answer, explanation, reference_links, weight, updated_at, created_at, updated_by FROM "quiz_question" WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetQuestionsByIds(ctx context.Context, ids []uuid.UUID) ([]QuizQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, getQuestionsByIds, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuizQuestion
+	for rows.Next() {
+		var i QuizQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuizID,
+			&i.QuestionType,
+			&i.Question,
+			pq.Array(&i.Options),
+			&i.CorrectOption,
+			&i.Answer,
+			&i.Explanation,
+			&i.ReferenceLinks,
+			&i.Weight,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getQuestionsByQuizId = `-- name: GetQuestionsByQuizId :many
-SELECT id, quiz_id, question, options, correct_option, updated_at, created_at, updated_by FROM "quiz_question" WHERE quiz_id = $1
+SELECT id, quiz_id, question_type, question, options, correct_option, answer, explanation, reference_links, weight, updated_at, created_at, updated_by FROM "quiz_question" WHERE quiz_id = $1
 `
 
 func (q *Queries) GetQuestionsByQuizId(ctx context.Context, quizID uuid.UUID) ([]QuizQuestion, error) {
@@ -29,9 +292,14 @@ func (q *Queries) GetQuestionsByQuizId(ctx context.Context, quizID uuid.UUID) ([
 		if err := rows.Scan(
 			&i.ID,
 			&i.QuizID,
+			&i.QuestionType,
 			&i.Question,
 			pq.Array(&i.Options),
 			&i.CorrectOption,
+			&i.Answer,
+			&i.Explanation,
+			&i.ReferenceLinks,
+			&i.Weight,
 			&i.UpdatedAt,
 			&i.CreatedAt,
 			&i.UpdatedBy,
@@ -49,6 +317,283 @@ func (q *Queries) GetQuestionsByQuizId(ctx context.Context, quizID uuid.UUID) ([
 	return items, nil
 }
 
+const getQuizById = `-- name: GetQuizById :one
+SELECT id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by FROM "quiz" WHERE id = $1
+`
+
+func (q *Queries) GetQuizById(ctx context.Context, id uuid.UUID) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, getQuizById, id)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const getQuizByTopicId = `-- name: GetQuizByTopicId :one
+SELECT id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by FROM "quiz" WHERE topic_id = $1
+`
+
+func (q *Queries) GetQuizByTopicId(ctx context.Context, topicID uuid.NullUUID) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, getQuizByTopicId, topicID)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const getQuizResultById = `-- name: GetQuizResultById :one
+SELECT
+    qr.id,
+    qr.quiz_id,
+    q.name AS quiz_name,
+    qr.score,
+    qr.grade,
+    qr.correct_answers,
+    qr.total_questions,
+    qr.created_at
+FROM quiz_result qr
+JOIN quiz q ON q.id = qr.quiz_id
+WHERE qr.id = $1
+AND qr.user_id = $2
+`
+
+type GetQuizResultByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetQuizResultByIdRow struct {
+	ID             uuid.UUID
+	QuizID         uuid.UUID
+	QuizName       sql.NullString
+	Score          sql.NullString
+	Grade          sql.NullString
+	CorrectAnswers int32
+	TotalQuestions int32
+	CreatedAt      sql.NullTime
+}
+
+func (q *Queries) GetQuizResultById(ctx context.Context, arg GetQuizResultByIdParams) (GetQuizResultByIdRow, error) {
+	row := q.db.QueryRowContext(ctx, getQuizResultById, arg.ID, arg.UserID)
+	var i GetQuizResultByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.QuizID,
+		&i.QuizName,
+		&i.Score,
+		&i.Grade,
+		&i.CorrectAnswers,
+		&i.TotalQuestions,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getQuizResultQuestions = `-- name: GetQuizResultQuestions :many
+SELECT
+    qq.id,
+    qq.question_type,
+    qq.question,
+    qq.options,
+    qq.correct_option,
+    qq.answer,
+    qrq.attempted_option,
+    qrq.attempted_answer,
+    qrq.is_correct
+FROM quiz_result_question qrq
+JOIN quiz_question qq ON qq.id = qrq.quiz_question_id
+WHERE qrq.quiz_result_id = $1
+ORDER BY qq.created_at, qq.id
+`
+
+type GetQuizResultQuestionsRow struct {
+	ID              uuid.UUID
+	QuestionType    QuizQuestionType
+	Question        sql.NullString
+	Options         []string
+	CorrectOption   sql.NullInt32
+	Answer          json.RawMessage
+	AttemptedOption sql.NullInt32
+	AttemptedAnswer json.RawMessage
+	IsCorrect       bool
+}
+
+func (q *Queries) GetQuizResultQuestions(ctx context.Context, quizResultID uuid.UUID) ([]GetQuizResultQuestionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuizResultQuestions, quizResultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuizResultQuestionsRow
+	for rows.Next() {
+		var i GetQuizResultQuestionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuestionType,
+			&i.Question,
+			pq.Array(&i.Options),
+			&i.CorrectOption,
+			&i.Answer,
+			&i.AttemptedOption,
+			&i.AttemptedAnswer,
+			&i.IsCorrect,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuizResultsByUserId = `-- name: GetQuizResultsByUserId :many
+SELECT
+    qr.id,
+    qr.quiz_id,
+    q.name AS quiz_name,
+    qr.score,
+    qr.grade,
+    qr.correct_answers,
+    qr.total_questions,
+    qr.created_at
+FROM quiz_result qr
+JOIN quiz q ON q.id = qr.quiz_id
+WHERE qr.user_id = $1
+ORDER BY qr.created_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetQuizResultsByUserIdParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type GetQuizResultsByUserIdRow struct {
+	ID             uuid.UUID
+	QuizID         uuid.UUID
+	QuizName       sql.NullString
+	Score          sql.NullString
+	Grade          sql.NullString
+	CorrectAnswers int32
+	TotalQuestions int32
+	CreatedAt      sql.NullTime
+}
+
+// attempts of a learner, latest first
+func (q *Queries) GetQuizResultsByUserId(ctx context.Context, arg GetQuizResultsByUserIdParams) ([]GetQuizResultsByUserIdRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuizResultsByUserId, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuizResultsByUserIdRow
+	for rows.Next() {
+		var i GetQuizResultsByUserIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuizID,
+			&i.QuizName,
+			&i.Score,
+			&i.Grade,
+			&i.CorrectAnswers,
+			&i.TotalQuestions,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementQuizPlayCount = `-- name: IncrementQuizPlayCount :exec
+UPDATE "quiz"
+SET
+    play_count = COALESCE(play_count, 0) + 1,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) IncrementQuizPlayCount(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, incrementQuizPlayCount, id)
+	return err
+}
+
+const insertTopicQuiz = `-- name: InsertTopicQuiz :one
+INSERT INTO
+    "quiz" (
+        name,
+        topic_id,
+        play_count
+    )
+VALUES ($1, $2, 0)
+ON CONFLICT (topic_id) WHERE topic_id IS NOT NULL DO NOTHING
+RETURNING id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by
+`
+
+type InsertTopicQuizParams struct {
+	Name    sql.NullString
+	TopicID uuid.NullUUID
+}
+
+// returns no rows when the topic got its quiz from a concurrent generation
+func (q *Queries) InsertTopicQuiz(ctx context.Context, arg InsertTopicQuizParams) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, insertTopicQuiz, arg.Name, arg.TopicID)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
 const saveQuiz = `-- name: SaveQuiz :one
 INSERT INTO
     "quiz" (
@@ -57,7 +602,7 @@ INSERT INTO
         play_count,
         updated_by
     )
-VALUES ($1, $2, $3, $4) RETURNING id, name, thumbnail_url, play_count, updated_at, created_at, updated_by
+VALUES ($1, $2, $3, $4) RETURNING id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by
 `
 
 type SaveQuizParams struct {
@@ -80,6 +625,11 @@ func (q *Queries) SaveQuiz(ctx context.Context, arg SaveQuizParams) (Quiz, error
 		&i.Name,
 		&i.ThumbnailUrl,
 		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
 		&i.UpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedBy,
@@ -91,37 +641,203 @@ const saveQuizQuestion = `-- name: SaveQuizQuestion :one
 INSERT INTO
     "quiz_question" (
         quiz_id,
+        question_type,
         question,
         options,
         correct_option,
+        answer,
+        explanation,
+        reference_links,
         updated_by
     )
-VALUES ($1, $2, $3, $4, $5) RETURNING id, quiz_id, question, options, correct_option, updated_at, created_at, updated_by
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, quiz_id, question_type, question, options, correct_option, answer, explanation, reference_links, weight, updated_at, created_at, updated_by
 `
 
 type SaveQuizQuestionParams struct {
-	QuizID        uuid.UUID
-	Question      sql.NullString
-	Options       []string
-	CorrectOption sql.NullInt32
-	UpdatedBy     uuid.NullUUID
+	QuizID         uuid.UUID
+	QuestionType   QuizQuestionType
+	Question       sql.NullString
+	Options        []string
+	CorrectOption  sql.NullInt32
+	Answer         json.RawMessage
+	Explanation    sql.NullString
+	ReferenceLinks json.RawMessage
+	UpdatedBy      uuid.NullUUID
 }
 
 func (q *Queries) SaveQuizQuestion(ctx context.Context, arg SaveQuizQuestionParams) (QuizQuestion, error) {
 	row := q.db.QueryRowContext(ctx, saveQuizQuestion,
 		arg.QuizID,
+		arg.QuestionType,
 		arg.Question,
 		pq.Array(arg.Options),
 		arg.CorrectOption,
+		arg.Answer,
+		arg.Explanation,
+		arg.ReferenceLinks,
 		arg.UpdatedBy,
 	)
 	var i QuizQuestion
 	err := row.Scan(
 		&i.ID,
 		&i.QuizID,
+		&i.QuestionType,
 		&i.Question,
 		pq.Array(&i.Options),
 		&i.CorrectOption,
+		&i.Answer,
+		&i.Explanation,
+		&i.ReferenceLinks,
+		&i.Weight,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const updateQuizQuestionWeight = `-- name: UpdateQuizQuestionWeight :execrows
+UPDATE "quiz_question"
+SET
+    weight = $3,
+    updated_by = $4,
+    updated_at = NOW()
+WHERE id = $1
+AND quiz_id = $2
+`
+
+type UpdateQuizQuestionWeightParams struct {
+	ID        uuid.UUID
+	QuizID    uuid.UUID
+	Weight    float64
+	UpdatedBy uuid.NullUUID
+}
+
+func (q *Queries) UpdateQuizQuestionWeight(ctx context.Context, arg UpdateQuizQuestionWeightParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateQuizQuestionWeight,
+		arg.ID,
+		arg.QuizID,
+		arg.Weight,
+		arg.UpdatedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateQuizScoring = `-- name: UpdateQuizScoring :one
+UPDATE "quiz"
+SET
+    negative_marking = $2,
+    grade_bands = $3,
+    updated_by = $4,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by
+`
+
+type UpdateQuizScoringParams struct {
+	ID              uuid.UUID
+	NegativeMarking float64
+	GradeBands      json.RawMessage
+	UpdatedBy       uuid.NullUUID
+}
+
+func (q *Queries) UpdateQuizScoring(ctx context.Context, arg UpdateQuizScoringParams) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, updateQuizScoring,
+		arg.ID,
+		arg.NegativeMarking,
+		arg.GradeBands,
+		arg.UpdatedBy,
+	)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const updateQuizTimeLimit = `-- name: UpdateQuizTimeLimit :one
+UPDATE "quiz"
+SET
+    time_limit_seconds = $2,
+    updated_by = $3,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by
+`
+
+type UpdateQuizTimeLimitParams struct {
+	ID               uuid.UUID
+	TimeLimitSeconds sql.NullInt32
+	UpdatedBy        uuid.NullUUID
+}
+
+func (q *Queries) UpdateQuizTimeLimit(ctx context.Context, arg UpdateQuizTimeLimitParams) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, updateQuizTimeLimit, arg.ID, arg.TimeLimitSeconds, arg.UpdatedBy)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const upsertPlaylistExam = `-- name: UpsertPlaylistExam :one
+INSERT INTO
+    "quiz" (
+        name,
+        playlist_id,
+        play_count
+    )
+SELECT
+    p.name || ' Exam',
+    p.id,
+    0
+FROM playlist p
+WHERE p.id = $1
+ON CONFLICT (playlist_id) WHERE playlist_id IS NOT NULL DO UPDATE
+SET
+    name = EXCLUDED.name
+RETURNING id, name, thumbnail_url, play_count, topic_id, playlist_id, time_limit_seconds, negative_marking, grade_bands, updated_at, created_at, updated_by
+`
+
+// returns the exam of a playlist, creating it on first use
+func (q *Queries) UpsertPlaylistExam(ctx context.Context, playlistID uuid.UUID) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, upsertPlaylistExam, playlistID)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ThumbnailUrl,
+		&i.PlayCount,
+		&i.TopicID,
+		&i.PlaylistID,
+		&i.TimeLimitSeconds,
+		&i.NegativeMarking,
+		&i.GradeBands,
 		&i.UpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedBy,
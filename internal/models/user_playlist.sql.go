@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createUserPlaylist = `-- name: CreateUserPlaylist :one
@@ -43,3 +44,57 @@ func (q *Queries) CreateUserPlaylist(ctx context.Context, arg CreateUserPlaylist
 	)
 	return i, err
 }
+
+const getPlaylistProgressByUserId = `-- name: GetPlaylistProgressByUserId :many
+SELECT
+    t.playlist_id,
+    COUNT(DISTINCT t.id) AS total_topics,
+    COUNT(DISTINCT t.id) FILTER (
+        WHERE uwv.is_watched = true
+    ) AS completed_topics
+FROM topic t
+LEFT JOIN youtube_video yv ON yv.topic_id = t.id
+LEFT JOIN user_watched_video uwv ON uwv.youtube_video_id = yv.id
+AND uwv.user_id = $1::uuid
+WHERE t.playlist_id = ANY($2::uuid[])
+GROUP BY t.playlist_id
+`
+
+type GetPlaylistProgressByUserIdParams struct {
+	UserID      uuid.UUID
+	PlaylistIds []uuid.UUID
+}
+
+type GetPlaylistProgressByUserIdRow struct {
+	PlaylistID      uuid.UUID
+	TotalTopics     int64
+	CompletedTopics int64
+}
+
+// a topic counts as completed once the user watched any of its videos
+func (q *Queries) GetPlaylistProgressByUserId(ctx context.Context, arg GetPlaylistProgressByUserIdParams) ([]GetPlaylistProgressByUserIdRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlaylistProgressByUserId, arg.UserID, pq.Array(arg.PlaylistIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlaylistProgressByUserIdRow
+	for rows.Next() {
+		var i GetPlaylistProgressByUserIdRow
+		if err := rows.Scan(
+			&i.PlaylistID,
+			&i.TotalTopics,
+			&i.CompletedTopics,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,17 +11,19 @@ import (
 )
 
 type GetPlaylistWithTopicsRow struct {
-	ID           uuid.UUID
-	Name         sql.NullString
-	Description  sql.NullString
-	Code         string
-	ThumbnailUrl sql.NullString
-	Views        sql.NullInt32
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
-	UpdatedBy    uuid.NullUUID
-	IsAIGen      bool
-	Topics       []dto.TopicsMiniDTO
+	ID            uuid.UUID
+	Name          sql.NullString
+	Description   sql.NullString
+	Code          string
+	ThumbnailUrl  sql.NullString
+	Views         sql.NullInt32
+	AverageRating float64
+	RatingCount   int32
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	UpdatedBy     uuid.NullUUID
+	IsAIGen       bool
+	Topics        []dto.TopicsMiniDTO
 }
 
 func GetPlaylistWithTopicsQuery(
@@ -33,8 +35,18 @@ func GetPlaylistWithTopicsQuery(
 			SELECT 
 				yv.topic_id,
 				yv.video_id,
-				ROW_NUMBER() OVER (PARTITION BY yv.topic_id ORDER BY yv.created_at DESC) AS rn
+				ROW_NUMBER() OVER (
+					PARTITION BY yv.topic_id
+					ORDER BY yv.is_pinned DESC, yv.rank_score DESC, yv.created_at DESC
+				) AS rn
 			FROM youtube_video yv
+			WHERE yv.is_hidden = false
+			AND NOT EXISTS (
+				SELECT 1
+				FROM video_ban vb
+				WHERE (vb.kind = 'video' AND vb.value = yv.video_id)
+				OR (vb.kind = 'channel' AND vb.value = yv.channel_id)
+			)
 		)
 		SELECT 
 				p.id, 
@@ -43,6 +55,8 @@ func GetPlaylistWithTopicsQuery(
 				p.code, 
 				p.thumbnail_url, 
 				p.views, 
+				p.average_rating, 
+				p.rating_count, 
 				p.created_at, 
 				p.updated_at, 
 				p.updated_by,
@@ -74,6 +88,8 @@ func GetPlaylistWithTopicsQuery(
 		&i.Code,
 		&i.ThumbnailUrl,
 		&i.Views,
+		&i.AverageRating,
+		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UpdatedBy,
@@ -89,3 +105,120 @@ func GetPlaylistWithTopicsQuery(
 	}
 	return i, nil
 }
+
+type GetPlaylistExportRow struct {
+	ID           uuid.UUID
+	Code         string
+	Name         sql.NullString
+	Description  sql.NullString
+	DomainName   sql.NullString
+	ThumbnailUrl sql.NullString
+	IsAIGen      bool
+	Topics       []dto.PlaylistBundleTopic
+}
+
+func GetPlaylistExportQuery(
+	ctx context.Context,
+	id uuid.UUID,
+) (GetPlaylistExportRow, error) {
+	const query = `
+		WITH ranked_videos AS (
+			SELECT 
+				yv.topic_id,
+				yv.video_id,
+				yv.provider,
+				yv.title,
+				yv.video_date,
+				yv.channel_title,
+				yv.channel_id,
+				yv.thumbnail_url,
+				yv.is_pinned,
+				yv.is_manual,
+				ROW_NUMBER() OVER (
+					PARTITION BY yv.topic_id
+					ORDER BY yv.is_pinned DESC, yv.rank_score DESC, yv.created_at DESC
+				) AS rn
+			FROM youtube_video yv
+			WHERE yv.is_hidden = false
+			AND NOT EXISTS (
+				SELECT 1
+				FROM video_ban vb
+				WHERE (vb.kind = 'video' AND vb.value = yv.video_id)
+				OR (vb.kind = 'channel' AND vb.value = yv.channel_id)
+			)
+		)
+		SELECT 
+				p.id, 
+				p.code, 
+				p.name, 
+				p.description, 
+				i.name AS domain_name,
+				p.thumbnail_url, 
+				p.is_ai_gen,
+				COALESCE(
+					JSON_AGG(
+						JSON_BUILD_OBJECT(
+							'name', t.name,
+							'topicNumber', t.number,
+							'videos', (
+								SELECT COALESCE(
+									JSON_AGG(
+										JSON_BUILD_OBJECT(
+											'videoId', rv.video_id,
+											'provider', rv.provider,
+											'title', rv.title,
+											'videoPublishedAt', TO_CHAR(rv.video_date, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+											'thumbnailUrl', rv.thumbnail_url,
+											'channelTitle', rv.channel_title,
+											'channelId', rv.channel_id,
+											'pinned', rv.is_pinned,
+											'manual', rv.is_manual
+										) ORDER BY rv.rn
+									),
+									'[]'::json
+								)
+								FROM ranked_videos rv
+								-- the best searched video plus every curated one
+								WHERE rv.topic_id = t.id
+								AND (rv.rn = 1 OR rv.is_pinned OR rv.is_manual)
+							),
+							'studyMaterial', (
+								SELECT JSON_BUILD_OBJECT(
+									'title', sm.title,
+									'content', sm.content
+								)
+								FROM study_material sm
+								WHERE sm.topic_id = t.id
+							)
+						) ORDER BY t.number
+					) FILTER (WHERE t.id IS NOT NULL),
+					'[]'::json
+				) AS topics
+		FROM playlist p
+		LEFT JOIN interest i ON i.id = p.interest_id
+		LEFT JOIN topic t ON p.id = t.playlist_id
+		WHERE p.id = $1
+		GROUP BY p.id, i.name
+	`
+	row := db.DB.QueryRowContext(ctx, query, id)
+	var i GetPlaylistExportRow
+	var topicsJSON []byte
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Description,
+		&i.DomainName,
+		&i.ThumbnailUrl,
+		&i.IsAIGen,
+		&topicsJSON,
+	)
+	if err != nil {
+		return i, err
+	}
+	// Unmarshal the JSON array into Topics
+	if err := json.Unmarshal(topicsJSON, &i.Topics); err != nil {
+		return i, err
+	}
+	return i, nil
+}
@@ -39,16 +39,28 @@ func GetTopicByIDWithVideos(ctx context.Context, id uuid.UUID) (GetTopicByIDWith
 					JSON_BUILD_OBJECT(
 						'id', yv.id,
 						'videoId', yv.video_id,
+						'provider', yv.provider,
 						'title', yv.title,
 						'topicId', yv.topic_id,
 						'videoPublishedAt', TO_CHAR(yv.video_date, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 						'channelTitle', yv.channel_title,
+						'channelId', yv.channel_id,
 						'thumbnailUrl', yv.thumbnail_url,
+						'durationSeconds', yv.duration_seconds,
+						'viewCount', yv.view_count,
+						'likeCount', yv.like_count,
+						'hasCaptions', yv.has_captions,
+						'definition', yv.definition,
+						'rankScore', yv.rank_score,
 						'expiryAt', TO_CHAR(yv.expiry_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+						'isPinned', yv.is_pinned,
+						'isManual', yv.is_manual,
+						'isHidden', yv.is_hidden,
 						'updatedAt', TO_CHAR(yv.updated_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 						'createdAt', TO_CHAR(yv.created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 						'updatedBy', yv.updated_by
 					)
+					ORDER BY yv.is_pinned DESC, yv.rank_score DESC, yv.created_at
 				) FILTER (WHERE yv.id IS NOT NULL),
 				'[]'::json
 			) AS videos
@@ -8,6 +8,7 @@ import (
 	"github.com/easc01/mindo-server/internal/models"
 	"github.com/easc01/mindo-server/pkg/db"
 	"github.com/easc01/mindo-server/pkg/dto"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
 	"github.com/google/uuid"
 )
 
@@ -84,6 +85,8 @@ func GetAppUserByUserID(
 												'interestId', p.interest_id,
 												'thumbnailUrl', p.thumbnail_url,
 												'views', p.views,
+												'averageRating', p.average_rating,
+												'ratingCount', p.rating_count,
 												'code', p.code,
 												'updatedAt', TO_CHAR(p.updated_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
 												'createdAt', TO_CHAR(p.created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
@@ -136,6 +139,9 @@ func GetAppUserByUserID(
 	if err := json.Unmarshal(communitiesJSON, &i.JoinedCommunities); err != nil {
 		return i, err
 	}
+	for k := range i.JoinedCommunities {
+		i.JoinedCommunities[k].AboutHTML = contentutil.HTML(i.JoinedCommunities[k].About)
+	}
 	// Unmarshal the JSON array into recent playlists
 	if err := json.Unmarshal(playlistsJSON, &i.RecentPlaylists); err != nil {
 		return i, err
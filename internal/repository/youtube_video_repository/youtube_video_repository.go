@@ -9,9 +9,17 @@ import (
 	"github.com/easc01/mindo-server/pkg/db"
 	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/util"
 	"github.com/google/uuid"
 )
 
+// VideoCacheTTL is how long searched videos are trusted before a refresh
+const VideoCacheTTL = 24 * time.Hour
+
+// videoColumns is the number of values inserted per video
+const videoColumns = 16
+
 func BatchInsertYoutubeVideos(
 	videos []dto.VideoMiniDTO,
 	topicId uuid.UUID,
@@ -19,41 +27,77 @@ func BatchInsertYoutubeVideos(
 ) ([]dto.VideoDataDTO, error) {
 	var placeholders []string
 	var values []interface{}
-	expiry := time.Now().Add(time.Hour * 24)
+	expiry := time.Now().Add(VideoCacheTTL)
+
+	// A single upsert cannot touch the same row twice, keep the first of each video
+	seen := make(map[string]bool, len(videos))
+	uniqueVideos := make([]dto.VideoMiniDTO, 0, len(videos))
+	for _, video := range videos {
+		if !seen[video.VideoID] {
+			seen[video.VideoID] = true
+			uniqueVideos = append(uniqueVideos, video)
+		}
+	}
+
+	if len(uniqueVideos) == 0 {
+		return []dto.VideoDataDTO{}, nil
+	}
 
 	// Construct placeholders and values
-	for i, video := range videos {
-		placeholders = append(
-			placeholders,
-			fmt.Sprintf(
-				"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-				i*8+1,
-				i*8+2,
-				i*8+3,
-				i*8+4,
-				i*8+5,
-				i*8+6,
-				i*8+7,
-				i*8+8,
-			),
-		)
+	for i, video := range uniqueVideos {
+		rowPlaceholders := make([]string, videoColumns)
+		for j := range rowPlaceholders {
+			rowPlaceholders[j] = fmt.Sprintf("$%d", i*videoColumns+j+1)
+		}
+		placeholders = append(placeholders, "("+strings.Join(rowPlaceholders, ", ")+")")
+
+		provider := video.Provider
+		if provider == constant.Blank {
+			provider = constant.VideoProviderYoutube
+		}
+
 		values = append(
 			values,
 			topicId,
 			video.Title,
 			video.ChannelTitle,
+			util.GetSQLNullString(video.ChannelID),
 			video.ThumbnailURL,
 			video.VideoID,
+			provider,
 			video.VideoDate,
+			util.GetSQLNullInt32(video.DurationSeconds),
+			util.GetSQLNullInt64(video.ViewCount),
+			util.GetSQLNullInt64(video.LikeCount),
+			video.HasCaptions,
+			util.GetSQLNullString(video.Definition),
+			video.RankScore,
 			expiry,
 			userId,
 		)
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO youtube_video (topic_id, title, channel_title, thumbnail_url, video_id, video_date, expiry_at, updated_by)
+		INSERT INTO youtube_video (topic_id, title, channel_title, channel_id, thumbnail_url, video_id, provider, video_date, duration_seconds, view_count, like_count, has_captions, definition, rank_score, expiry_at, updated_by)
 		VALUES %s
-		RETURNING id, topic_id, title, channel_title, thumbnail_url, video_id, video_date, expiry_at, created_at, updated_at, updated_by
+		ON CONFLICT (topic_id, video_id) DO UPDATE
+		SET
+			provider = EXCLUDED.provider,
+			title = EXCLUDED.title,
+			channel_title = EXCLUDED.channel_title,
+			channel_id = EXCLUDED.channel_id,
+			thumbnail_url = EXCLUDED.thumbnail_url,
+			video_date = EXCLUDED.video_date,
+			duration_seconds = EXCLUDED.duration_seconds,
+			view_count = EXCLUDED.view_count,
+			like_count = EXCLUDED.like_count,
+			has_captions = EXCLUDED.has_captions,
+			definition = EXCLUDED.definition,
+			rank_score = EXCLUDED.rank_score,
+			expiry_at = EXCLUDED.expiry_at,
+			updated_at = NOW(),
+			updated_by = EXCLUDED.updated_by
+		RETURNING id, topic_id, title, channel_title, COALESCE(channel_id, ''), thumbnail_url, video_id, provider, video_date, COALESCE(duration_seconds, 0), COALESCE(view_count, 0), COALESCE(like_count, 0), has_captions, COALESCE(definition, ''), rank_score, expiry_at, is_pinned, is_manual, is_hidden, created_at, updated_at, updated_by
 	`, strings.Join(placeholders, ", "))
 
 	// Execute query in transaction
@@ -64,8 +108,8 @@ func BatchInsertYoutubeVideos(
 	}
 
 	logger.Log.Infof(
-		"inserted %d youtube videos during batch insert to topic id %s",
-		len(videos),
+		"upserted %d youtube videos during batch insert to topic id %s",
+		len(uniqueVideos),
 		topicId,
 	)
 	defer rows.Close()
@@ -78,10 +122,21 @@ func BatchInsertYoutubeVideos(
 			&video.TopicID,
 			&video.Title,
 			&video.ChannelTitle,
+			&video.ChannelID,
 			&video.ThumbnailURL,
 			&video.VideoID,
+			&video.Provider,
 			&video.VideoDate,
+			&video.DurationSeconds,
+			&video.ViewCount,
+			&video.LikeCount,
+			&video.HasCaptions,
+			&video.Definition,
+			&video.RankScore,
 			&video.ExpiryAt,
+			&video.IsPinned,
+			&video.IsManual,
+			&video.IsHidden,
 			&video.CreatedAt,
 			&video.UpdatedAt,
 			&video.UpdatedBy,
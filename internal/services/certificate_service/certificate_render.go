@@ -0,0 +1,48 @@
+package certificateservice
+
+import (
+	"bytes"
+	"net/http"
+	"text/template"
+
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const ContentTypeSVG = "image/svg+xml"
+
+// certificateSVG is an A4 landscape certificate, text values are XML escaped by the xml func
+var certificateSVG = template.Must(template.New("certificate").Funcs(template.FuncMap{
+	"xml": template.HTMLEscapeString,
+}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="1123" height="794" viewBox="0 0 1123 794">
+  <rect width="1123" height="794" fill="#fdfbf6"/>
+  <rect x="30" y="30" width="1063" height="734" fill="none" stroke="#2f3b69" stroke-width="6"/>
+  <rect x="48" y="48" width="1027" height="698" fill="none" stroke="#c9a646" stroke-width="2"/>
+  <text x="561.5" y="170" font-family="Georgia, serif" font-size="56" fill="#2f3b69" text-anchor="middle">Certificate of Completion</text>
+  <text x="561.5" y="250" font-family="Helvetica, Arial, sans-serif" font-size="22" fill="#555" text-anchor="middle">This certifies that</text>
+  <text x="561.5" y="330" font-family="Georgia, serif" font-size="48" fill="#111" text-anchor="middle">{{xml .LearnerName}}</text>
+  <line x1="261.5" y1="355" x2="861.5" y2="355" stroke="#c9a646" stroke-width="2"/>
+  <text x="561.5" y="420" font-family="Helvetica, Arial, sans-serif" font-size="22" fill="#555" text-anchor="middle">has completed every topic of the playlist</text>
+  <text x="561.5" y="480" font-family="Georgia, serif" font-size="36" fill="#2f3b69" text-anchor="middle">{{xml .PlaylistName}}</text>
+  <text x="561.5" y="560" font-family="Helvetica, Arial, sans-serif" font-size="20" fill="#555" text-anchor="middle">Issued on {{.IssuedAt.Format "January 2, 2006"}}</text>
+  <text x="561.5" y="690" font-family="Courier New, monospace" font-size="18" fill="#333" text-anchor="middle">Verification code {{xml .Code}}</text>
+  <text x="561.5" y="718" font-family="Helvetica, Arial, sans-serif" font-size="14" fill="#777" text-anchor="middle">Verify at /api/certificates/{{xml .Code}}/verify</text>
+</svg>
+`))
+
+// RenderCertificateSVG draws the certificate of the given code as a standalone SVG document
+func RenderCertificateSVG(c *gin.Context, code string) ([]byte, int, error) {
+	certificate, statusCode, err := getCertificateByCode(c, code)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	var buf bytes.Buffer
+	if err := certificateSVG.Execute(&buf, serializeCertificate(certificate)); err != nil {
+		logger.Log.Errorf("failed to render certificate %s, %s", certificate.Code, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return buf.Bytes(), http.StatusOK, nil
+}
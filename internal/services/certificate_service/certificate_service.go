@@ -0,0 +1,292 @@
+package certificateservice
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/encrypt"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const SignatureAlgorithm = "HMAC-SHA256"
+
+// IssueCertificate returns the completion certificate of a playlist to the app
+// user, certificates are issued on completion so this mostly re-fetches one, a
+// learner who became eligible before that gets theirs issued here
+func IssueCertificate(c *gin.Context, playlistID uuid.UUID) (dto.CertificateDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.CertificateDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	certificate, statusCode, err := issueCertificate(c, user.AppUser.UserID, playlistID)
+	if err != nil {
+		return dto.CertificateDTO{}, statusCode, err
+	}
+
+	return serializeCertificate(certificate), statusCode, nil
+}
+
+// IssueEarnedCertificate issues the certificate of a playlist once the learner
+// is eligible for it, it is called after every completion and does nothing
+// while the learner is not eligible or already has one
+func IssueEarnedCertificate(ctx context.Context, userID uuid.UUID, playlistID uuid.UUID) error {
+	_, statusCode, err := issueCertificate(ctx, userID, playlistID)
+	if statusCode == http.StatusForbidden {
+		return nil
+	}
+	return err
+}
+
+// IssueEarnedTopicCertificate is IssueEarnedCertificate for the playlist of a topic
+func IssueEarnedTopicCertificate(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) error {
+	playlistID, err := db.Queries.GetPlaylistIdByTopicId(ctx, topicID)
+	if err != nil {
+		return err
+	}
+	return IssueEarnedCertificate(ctx, userID, playlistID)
+}
+
+// issueCertificate creates the certificate of an eligible learner, issuing
+// again returns the certificate that was already issued
+func issueCertificate(ctx context.Context, userID uuid.UUID, playlistID uuid.UUID) (models.Certificate, int, error) {
+	playlist, err := db.Queries.GetPlaylistById(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Certificate{}, http.StatusNotFound, fmt.Errorf(
+				"playlist of id %s not found",
+				playlistID,
+			)
+		}
+		logger.Log.Errorf("failed to get playlist of id %s, %s", playlistID, err.Error())
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	if statusCode, err := checkEligibility(ctx, userID, playlistID); err != nil {
+		return models.Certificate{}, statusCode, err
+	}
+
+	learner, err := db.Queries.GetCertificateLearner(ctx, userID)
+	if err != nil {
+		logger.Log.Errorf("failed to get app user %s, %s", userID, err.Error())
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		logger.Log.Errorf("failed to generate certificate code, %s", err.Error())
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	learnerName := learner.Name.String
+	if learnerName == constant.Blank {
+		learnerName = learner.Username.String
+	}
+
+	payload := dto.CertificatePayload{
+		Code:         code,
+		LearnerName:  learnerName,
+		PlaylistID:   playlistID,
+		PlaylistName: playlist.Name.String,
+		IssuedAt:     time.Now().UTC().Truncate(time.Second),
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	certificate, err := db.Queries.CreateCertificate(ctx, models.CreateCertificateParams{
+		Code:         payload.Code,
+		UserID:       userID,
+		PlaylistID:   playlistID,
+		LearnerName:  payload.LearnerName,
+		PlaylistName: payload.PlaylistName,
+		IssuedAt:     payload.IssuedAt,
+		Signature:    encrypt.SignHMAC(payloadBytes, config.GetConfig().CertificateSecret),
+		UpdatedBy:    util.GetNullUUID(userID),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to create certificate of playlist %s, %s", playlistID, err.Error())
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	statusCode := http.StatusCreated
+	if certificate.Code != code {
+		statusCode = http.StatusOK
+	}
+
+	return certificate, statusCode, nil
+}
+
+func GetUserCertificates(c *gin.Context) ([]dto.CertificateDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return []dto.CertificateDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	certificates, err := db.Queries.GetCertificatesByUserId(c, user.AppUser.UserID)
+	if err != nil {
+		logger.Log.Errorf("failed to get certificates of user %s, %s", user.AppUser.UserID, err.Error())
+		return []dto.CertificateDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedCertificates := make([]dto.CertificateDTO, len(certificates))
+	for i, certificate := range certificates {
+		serializedCertificates[i] = serializeCertificate(certificate)
+	}
+
+	return serializedCertificates, http.StatusAccepted, nil
+}
+
+// VerifyCertificate looks a certificate up by its public code and re-checks its signature
+func VerifyCertificate(c *gin.Context, code string) (dto.CertificateVerificationDTO, int, error) {
+	certificate, statusCode, err := getCertificateByCode(c, code)
+	if err != nil {
+		return dto.CertificateVerificationDTO{}, statusCode, err
+	}
+
+	payloadBytes, err := json.Marshal(dto.CertificatePayload{
+		Code:         certificate.Code,
+		LearnerName:  certificate.LearnerName,
+		PlaylistID:   certificate.PlaylistID,
+		PlaylistName: certificate.PlaylistName,
+		IssuedAt:     certificate.IssuedAt.UTC(),
+	})
+	if err != nil {
+		return dto.CertificateVerificationDTO{}, http.StatusInternalServerError, err
+	}
+
+	return dto.CertificateVerificationDTO{
+		Valid: encrypt.VerifyHMAC(
+			payloadBytes,
+			certificate.Signature,
+			config.GetConfig().CertificateSecret,
+		),
+		Code:         certificate.Code,
+		LearnerName:  certificate.LearnerName,
+		PlaylistID:   certificate.PlaylistID,
+		PlaylistName: certificate.PlaylistName,
+		IssuedAt:     certificate.IssuedAt,
+		Payload:      base64.RawURLEncoding.EncodeToString(payloadBytes),
+		Signature:    certificate.Signature,
+		Algorithm:    SignatureAlgorithm,
+	}, http.StatusAccepted, nil
+}
+
+// checkEligibility requires every topic of the playlist to be completed, a
+// passing score on every topic quiz and on the exam of playlists with quizzes
+func checkEligibility(ctx context.Context, userID uuid.UUID, playlistID uuid.UUID) (int, error) {
+	progress, err := playlistservice.GetPlaylistProgress(ctx, userID, []uuid.UUID{playlistID})
+	if err != nil {
+		logger.Log.Errorf("failed to get progress of user %s, %s", userID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	playlistProgress := progress[playlistID]
+	if !playlistservice.IsPlaylistCompleted(playlistProgress) {
+		return http.StatusForbidden, fmt.Errorf(
+			"playlist not completed, %d of %d topics done",
+			playlistProgress.CompletedTopics,
+			playlistProgress.TotalTopics,
+		)
+	}
+
+	scores, err := db.Queries.GetPlaylistQuizBestScores(ctx, models.GetPlaylistQuizBestScoresParams{
+		UserID:     userID,
+		PlaylistID: playlistID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get quiz scores of user %s, %s", userID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	// the exam is created on its first start, a playlist with topic quizzes
+	// has one even before that
+	passPercent := float64(config.GetConfig().ExamPassPercent)
+	examPassed := len(scores) == 0
+	for _, score := range scores {
+		passed := score.BestScore.Valid && score.BestScore.Float64 >= passPercent
+		if !score.TopicID.Valid {
+			examPassed = passed
+			continue
+		}
+		if !passed {
+			return http.StatusForbidden, fmt.Errorf(
+				"quiz of topic %s not passed, a score of %g%% is needed",
+				score.TopicName.String,
+				passPercent,
+			)
+		}
+	}
+
+	if !examPassed {
+		return http.StatusForbidden, fmt.Errorf(
+			"playlist exam not passed, a score of %g%% is needed",
+			passPercent,
+		)
+	}
+
+	return http.StatusOK, nil
+}
+
+func getCertificateByCode(c *gin.Context, code string) (models.Certificate, int, error) {
+	certificate, err := db.Queries.GetCertificateByCode(c, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Certificate{}, http.StatusNotFound, fmt.Errorf(
+				"certificate of code %s not found",
+				code,
+			)
+		}
+		logger.Log.Errorf("failed to get certificate of code %s, %s", code, err.Error())
+		return models.Certificate{}, http.StatusInternalServerError, err
+	}
+
+	return certificate, http.StatusOK, nil
+}
+
+// generateCode returns a random code like ABCD-EFGH-IJKL-MNOP
+func generateCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return constant.Blank, err
+	}
+
+	raw := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+func serializeCertificate(certificate models.Certificate) dto.CertificateDTO {
+	return dto.CertificateDTO{
+		ID:           certificate.ID,
+		Code:         certificate.Code,
+		PlaylistID:   certificate.PlaylistID,
+		PlaylistName: certificate.PlaylistName,
+		LearnerName:  certificate.LearnerName,
+		IssuedAt:     certificate.IssuedAt,
+	}
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
+	moderationservice "github.com/easc01/mindo-server/internal/services/moderation_service"
 	"github.com/easc01/mindo-server/pkg/db"
 	"github.com/easc01/mindo-server/pkg/dto"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
 	"github.com/easc01/mindo-server/pkg/utils/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,10 +23,15 @@ func SaveCommunityMessage(
 	userID uuid.UUID,
 	msg string,
 ) (models.CreateMessageRow, error) {
+	moderatedMsg, err := moderationservice.ModerateContent(c, msg)
+	if err != nil {
+		return models.CreateMessageRow{}, err
+	}
+
 	return db.Queries.CreateMessage(c, models.CreateMessageParams{
 		CommunityID: communityID,
 		UserID:      userID,
-		Content:     util.GetSQLNullString(msg),
+		Content:     util.GetSQLNullString(moderatedMsg),
 		UpdatedBy:   util.GetNullUUID(userID),
 	})
 }
@@ -86,9 +93,10 @@ func serializeUserMessages(messages []models.GetMessagePageByCommunityIDRow) []d
 				) <= timeFrame {
 
 				lastGroup.Messages = append(lastGroup.Messages, dto.MessageDTO{
-					ID:        message.ID,
-					Content:   message.Content.String,
-					Timestamp: message.CreatedAt.Time,
+					ID:          message.ID,
+					Content:     message.Content.String,
+					ContentHTML: contentutil.HTML(message.Content.String),
+					Timestamp:   message.CreatedAt.Time,
 				})
 
 				continue
@@ -104,9 +112,10 @@ func serializeUserMessages(messages []models.GetMessagePageByCommunityIDRow) []d
 			UserColor:      message.Color,
 			Messages: []dto.MessageDTO{
 				{
-					ID:        message.ID,
-					Content:   message.Content.String,
-					Timestamp: message.CreatedAt.Time,
+					ID:          message.ID,
+					Content:     message.Content.String,
+					ContentHTML: contentutil.HTML(message.Content.String),
+					Timestamp:   message.CreatedAt.Time,
 				},
 			},
 		})
@@ -9,6 +9,7 @@ import (
 	"github.com/easc01/mindo-server/pkg/db"
 	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/logger"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
 	"github.com/easc01/mindo-server/pkg/utils/message"
 	"github.com/easc01/mindo-server/pkg/utils/util"
 	"github.com/gin-gonic/gin"
@@ -74,6 +75,7 @@ func CreateNewCommunity(
 		ID:           community.ID,
 		Title:        community.Title.String,
 		About:        community.About.String,
+		AboutHTML:    contentutil.HTML(community.About.String),
 		ThumbnailUrl: community.ThumbnailUrl.String,
 		LogoUrl:      community.LogoUrl.String,
 		CreatedAt:    community.CreatedAt.Time,
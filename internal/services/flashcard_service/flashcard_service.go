@@ -0,0 +1,332 @@
+package flashcardservice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetTopicFlashcardDeck returns the authored cards of a topic, for learners it
+// also tells whether they study the deck
+func GetTopicFlashcardDeck(c *gin.Context, topicID uuid.UUID) (dto.FlashcardDeckDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		return dto.FlashcardDeckDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullUserContext)
+	}
+
+	var userID uuid.UUID
+	if user.AppUser != nil {
+		userID = user.AppUser.UserID
+	}
+
+	deck, err := db.Queries.GetFlashcardDeckByTopicId(c, models.GetFlashcardDeckByTopicIdParams{
+		UserID:  userID,
+		TopicID: topicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.FlashcardDeckDTO{}, http.StatusNotFound, fmt.Errorf("flashcards of topic %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get flashcard deck of topic %s, %s", topicID, err.Error())
+		return dto.FlashcardDeckDTO{}, http.StatusInternalServerError, err
+	}
+
+	cards, err := db.Queries.GetFlashcardsByDeckId(c, deck.ID)
+	if err != nil {
+		logger.Log.Errorf("failed to get flashcards of deck %s, %s", deck.ID, err.Error())
+		return dto.FlashcardDeckDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedCards := make([]dto.FlashcardDTO, len(cards))
+	for i, card := range cards {
+		serializedCards[i] = serializeFlashcard(card)
+	}
+
+	return dto.FlashcardDeckDTO{
+		ID:         deck.ID,
+		TopicID:    deck.TopicID.UUID,
+		Name:       deck.Name,
+		IsStudying: deck.IsStudying,
+		Cards:      serializedCards,
+	}, http.StatusAccepted, nil
+}
+
+// AddTopicFlashcard authors a card in the deck of a topic, the deck is created
+// with the first card
+func AddTopicFlashcard(
+	c *gin.Context,
+	topicID uuid.UUID,
+	req dto.FlashcardRequest,
+) (dto.FlashcardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.FlashcardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	deck, err := db.Queries.UpsertTopicFlashcardDeck(c, models.UpsertTopicFlashcardDeckParams{
+		TopicID:   topicID,
+		UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.FlashcardDTO{}, http.StatusNotFound, fmt.Errorf("topic of id %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get flashcard deck of topic %s, %s", topicID, err.Error())
+		return dto.FlashcardDTO{}, http.StatusInternalServerError, err
+	}
+
+	card, err := db.Queries.CreateFlashcard(c, models.CreateFlashcardParams{
+		DeckID:    deck.ID,
+		Front:     req.Front,
+		Back:      req.Back,
+		UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to create flashcard in deck %s, %s", deck.ID, err.Error())
+		return dto.FlashcardDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeFlashcard(card), http.StatusCreated, nil
+}
+
+func UpdateFlashcard(
+	c *gin.Context,
+	cardID uuid.UUID,
+	req dto.FlashcardRequest,
+) (dto.FlashcardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.FlashcardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	card, err := db.Queries.UpdateFlashcard(c, models.UpdateFlashcardParams{
+		ID:        cardID,
+		Front:     req.Front,
+		Back:      req.Back,
+		UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.FlashcardDTO{}, http.StatusNotFound, fmt.Errorf("flashcard of id %s not found", cardID)
+		}
+		logger.Log.Errorf("failed to update flashcard %s, %s", cardID, err.Error())
+		return dto.FlashcardDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeFlashcard(card), http.StatusAccepted, nil
+}
+
+// DeleteFlashcard removes a card along with every learner's review state of it
+func DeleteFlashcard(c *gin.Context, cardID uuid.UUID) (int, error) {
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.DeleteUserFlashcardsByFlashcardId(c, cardID); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to delete review state of flashcard %s, %s", cardID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	deleted, err := qtx.DeleteFlashcardById(c, cardID)
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to delete flashcard %s, %s", cardID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		tx.Rollback()
+		return http.StatusNotFound, fmt.Errorf("flashcard of id %s not found", cardID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// StudyTopicFlashcards adds the deck of a topic to the learner's reviews, cards
+// added to the deck later show up as new cards too
+func StudyTopicFlashcards(c *gin.Context, topicID uuid.UUID) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	deck, statusCode, err := getTopicDeckID(c, topicID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	if err := db.Queries.StudyFlashcardDeck(c, models.StudyFlashcardDeckParams{
+		UserID: user.AppUser.UserID,
+		DeckID: deck,
+	}); err != nil {
+		logger.Log.Errorf("failed to study flashcard deck %s, %s", deck, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// StopStudyingTopicFlashcards removes the deck from new cards, cards already
+// reviewed keep their schedule
+func StopStudyingTopicFlashcards(c *gin.Context, topicID uuid.UUID) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	deck, statusCode, err := getTopicDeckID(c, topicID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	removed, err := db.Queries.StopStudyingFlashcardDeck(c, models.StopStudyingFlashcardDeckParams{
+		UserID: user.AppUser.UserID,
+		DeckID: deck,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to stop studying flashcard deck %s, %s", deck, err.Error())
+		return http.StatusInternalServerError, err
+	}
+	if removed == 0 {
+		return http.StatusNotFound, fmt.Errorf("flashcards of topic %s are not studied", topicID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// AddMissedQuestions turns the questions a learner got wrong into cards of the
+// quiz deck that are due right away
+func AddMissedQuestions(
+	ctx context.Context,
+	userID uuid.UUID,
+	quizID uuid.UUID,
+	missed []models.QuizQuestion,
+) error {
+	if len(missed) == 0 {
+		return nil
+	}
+
+	deck, err := db.Queries.UpsertQuizFlashcardDeck(ctx, quizID)
+	if err != nil {
+		return fmt.Errorf("failed to get flashcard deck of quiz %s, %w", quizID, err)
+	}
+
+	for _, question := range missed {
+		card, err := db.Queries.UpsertQuestionFlashcard(ctx, models.UpsertQuestionFlashcardParams{
+			DeckID:         deck.ID,
+			Front:          question.Question.String,
+			Back:           correctAnswer(question),
+			QuizQuestionID: util.GetNullUUID(question.ID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save flashcard of question %s, %w", question.ID, err)
+		}
+
+		if err := db.Queries.ScheduleMissedFlashcard(ctx, models.ScheduleMissedFlashcardParams{
+			UserID:      userID,
+			FlashcardID: card.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to schedule flashcard %s, %w", card.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// correctAnswer returns the text of the right answer by question type, option
+// numbers start at 1 like the generator's optionNumber
+func correctAnswer(question models.QuizQuestion) string {
+	optionText := func(number int) string {
+		if number < 1 || number > len(question.Options) {
+			return fmt.Sprintf("Option %d", number)
+		}
+		return question.Options[number-1]
+	}
+
+	var key dto.QuizAnswerKey
+	if len(question.Answer) > 0 {
+		_ = json.Unmarshal(question.Answer, &key)
+	}
+
+	switch question.QuestionType {
+	case models.QuizQuestionTypeMultiSelect, models.QuizQuestionTypeOrdering:
+		texts := make([]string, len(key.Options))
+		for i, number := range key.Options {
+			texts[i] = optionText(number)
+		}
+		if question.QuestionType == models.QuizQuestionTypeOrdering {
+			return strings.Join(texts, " → ")
+		}
+		return strings.Join(texts, ", ")
+
+	case models.QuizQuestionTypeNumeric:
+		if key.Value == nil {
+			return ""
+		}
+		value := strconv.FormatFloat(*key.Value, 'f', -1, 64)
+		if key.Tolerance > 0 {
+			return fmt.Sprintf("%s (± %s)", value, strconv.FormatFloat(key.Tolerance, 'f', -1, 64))
+		}
+		return value
+
+	case models.QuizQuestionTypeShortText:
+		if len(key.Accepted) > 0 {
+			return key.Accepted[0]
+		}
+		return key.Pattern
+
+	default:
+		return optionText(int(question.CorrectOption.Int32))
+	}
+}
+
+func getTopicDeckID(c *gin.Context, topicID uuid.UUID) (uuid.UUID, int, error) {
+	deck, err := db.Queries.GetFlashcardDeckByTopicId(c, models.GetFlashcardDeckByTopicIdParams{
+		TopicID: topicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, http.StatusNotFound, fmt.Errorf("flashcards of topic %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get flashcard deck of topic %s, %s", topicID, err.Error())
+		return uuid.Nil, http.StatusInternalServerError, err
+	}
+
+	return deck.ID, http.StatusOK, nil
+}
+
+func serializeFlashcard(card models.Flashcard) dto.FlashcardDTO {
+	return dto.FlashcardDTO{
+		ID:        card.ID,
+		DeckID:    card.DeckID,
+		Front:     card.Front,
+		FrontHTML: contentutil.HTML(card.Front),
+		Back:      card.Back,
+		BackHTML:  contentutil.HTML(card.Back),
+		IsDerived: card.QuizQuestionID.Valid,
+		UpdatedAt: card.UpdatedAt.Time,
+		CreatedAt: card.CreatedAt.Time,
+	}
+}
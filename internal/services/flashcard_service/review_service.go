@@ -0,0 +1,111 @@
+package flashcardservice
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetDueFlashcards returns the learner's cards due for review, overdue cards
+// come before cards never reviewed
+func GetDueFlashcards(c *gin.Context, limit int) ([]dto.DueFlashcardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return []dto.DueFlashcardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	rows, err := db.Queries.GetDueFlashcards(c, models.GetDueFlashcardsParams{
+		UserID:   user.AppUser.UserID,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get due flashcards, %s", err.Error())
+		return []dto.DueFlashcardDTO{}, http.StatusInternalServerError, err
+	}
+
+	cards := make([]dto.DueFlashcardDTO, len(rows))
+	for i, row := range rows {
+		cards[i] = dto.DueFlashcardDTO{
+			ID:           row.ID,
+			DeckID:       row.DeckID,
+			DeckName:     row.DeckName,
+			TopicID:      row.TopicID.UUID,
+			Front:        row.Front,
+			FrontHTML:    contentutil.HTML(row.Front),
+			Back:         row.Back,
+			BackHTML:     contentutil.HTML(row.Back),
+			IsNew:        row.IsNew,
+			Repetitions:  int(row.Repetitions),
+			IntervalDays: int(row.IntervalDays),
+			EaseFactor:   row.EaseFactor,
+			DueAt:        row.DueAt.Time,
+		}
+	}
+
+	return cards, http.StatusAccepted, nil
+}
+
+// ReviewFlashcard grades the learner's recall of a card and schedules its next
+// review with SM-2
+func ReviewFlashcard(c *gin.Context, cardID uuid.UUID, grade int) (dto.FlashcardReviewDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.FlashcardReviewDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	current, err := db.Queries.GetUserFlashcardForReview(c, models.GetUserFlashcardForReviewParams{
+		UserID:      user.AppUser.UserID,
+		FlashcardID: cardID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.FlashcardReviewDTO{}, http.StatusNotFound, fmt.Errorf("flashcard %s is not in your reviews", cardID)
+		}
+		logger.Log.Errorf("failed to get review state of flashcard %s, %s", cardID, err.Error())
+		return dto.FlashcardReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	next, dueAt := schedule(reviewState{
+		easeFactor:   current.EaseFactor,
+		intervalDays: int(current.IntervalDays),
+		repetitions:  int(current.Repetitions),
+		lapses:       int(current.Lapses),
+	}, grade, time.Now())
+
+	saved, err := db.Queries.SaveFlashcardReview(c, models.SaveFlashcardReviewParams{
+		UserID:       user.AppUser.UserID,
+		FlashcardID:  cardID,
+		EaseFactor:   next.easeFactor,
+		IntervalDays: int32(next.intervalDays),
+		Repetitions:  int32(next.repetitions),
+		Lapses:       int32(next.lapses),
+		LastGrade:    sql.NullInt32{Int32: int32(grade), Valid: true},
+		DueAt:        dueAt,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to save review of flashcard %s, %s", cardID, err.Error())
+		return dto.FlashcardReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	return dto.FlashcardReviewDTO{
+		CardID:       saved.FlashcardID,
+		Grade:        grade,
+		Repetitions:  int(saved.Repetitions),
+		IntervalDays: int(saved.IntervalDays),
+		EaseFactor:   saved.EaseFactor,
+		Lapses:       int(saved.Lapses),
+		DueAt:        saved.DueAt,
+	}, http.StatusAccepted, nil
+}
@@ -0,0 +1,46 @@
+package flashcardservice
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	minEaseFactor  = 1.3
+	passingGrade   = 3
+	firstInterval  = 1
+	secondInterval = 6
+)
+
+// reviewState is the SM-2 scheduling state of one card for one learner
+type reviewState struct {
+	easeFactor   float64
+	intervalDays int
+	repetitions  int
+	lapses       int
+}
+
+// schedule applies a 0-5 recall grade to the state following SM-2, grades
+// below 3 restart the card while keeping the lowered ease factor
+func schedule(state reviewState, grade int, now time.Time) (reviewState, time.Time) {
+	if grade < passingGrade {
+		state.repetitions = 0
+		state.intervalDays = firstInterval
+		state.lapses++
+	} else {
+		switch state.repetitions {
+		case 0:
+			state.intervalDays = firstInterval
+		case 1:
+			state.intervalDays = secondInterval
+		default:
+			state.intervalDays = int(math.Round(float64(state.intervalDays) * state.easeFactor))
+		}
+		state.repetitions++
+	}
+
+	miss := float64(5 - grade)
+	state.easeFactor = max(minEaseFactor, state.easeFactor+0.1-miss*(0.08+miss*0.02))
+
+	return state, now.AddDate(0, 0, state.intervalDays)
+}
@@ -0,0 +1,269 @@
+package learningpathservice
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func CreateLearningPath(
+	c *gin.Context,
+	req dto.CreateLearningPathRequest,
+) (dto.LearningPathDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.LearningPathDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+	adminID := user.AdminUser.UserID
+
+	if statusCode, err := validateStepPlaylists(c, req.PlaylistIDs); err != nil {
+		return dto.LearningPathDTO{}, statusCode, err
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	path, err := qtx.CreateLearningPath(c, models.CreateLearningPathParams{
+		Name:         req.Name,
+		Description:  util.GetSQLNullString(req.Description),
+		ThumbnailUrl: util.GetSQLNullString(req.ThumbnailURL),
+		UpdatedBy:    util.GetNullUUID(adminID),
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to create learning path, %s", err.Error())
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := insertSteps(c, qtx, path.ID, req.PlaylistIDs, adminID); err != nil {
+		tx.Rollback()
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	learningPath, statusCode, err := GetLearningPath(c, path.ID)
+	if err != nil {
+		return dto.LearningPathDTO{}, statusCode, err
+	}
+	return learningPath, http.StatusCreated, nil
+}
+
+// ReplaceLearningPathSteps swaps the ordered playlists of a path for the given ones
+func ReplaceLearningPathSteps(
+	c *gin.Context,
+	pathID uuid.UUID,
+	req dto.UpdateLearningPathStepsRequest,
+) (dto.LearningPathDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.LearningPathDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	if _, err := db.Queries.GetLearningPathById(c, pathID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LearningPathDTO{}, http.StatusNotFound, fmt.Errorf(
+				"learning path of id %s not found",
+				pathID,
+			)
+		}
+		logger.Log.Errorf("failed to get learning path of id %s, %s", pathID, err.Error())
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	if statusCode, err := validateStepPlaylists(c, req.PlaylistIDs); err != nil {
+		return dto.LearningPathDTO{}, statusCode, err
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.DeleteLearningPathSteps(c, pathID); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to clear steps of learning path %s, %s", pathID, err.Error())
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := insertSteps(c, qtx, pathID, req.PlaylistIDs, user.AdminUser.UserID); err != nil {
+		tx.Rollback()
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	return GetLearningPath(c, pathID)
+}
+
+func DeleteLearningPath(c *gin.Context, pathID uuid.UUID) (int, error) {
+	deleted, err := db.Queries.DeleteLearningPathById(c, pathID)
+	if err != nil {
+		logger.Log.Errorf("failed to delete learning path %s, %s", pathID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf("learning path of id %s not found", pathID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+func GetAllLearningPaths(c *gin.Context) ([]dto.LearningPathDTO, int, error) {
+	paths, err := db.Queries.GetAllLearningPaths(c)
+	if err != nil {
+		logger.Log.Errorf("failed to get learning paths, %s", err.Error())
+		return []dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedPaths := make([]dto.LearningPathDTO, len(paths))
+	for i, path := range paths {
+		serializedPaths[i] = dto.LearningPathDTO{
+			ID:           path.ID,
+			Name:         path.Name,
+			Description:  path.Description.String,
+			ThumbnailURL: path.ThumbnailUrl.String,
+			StepsCount:   int(path.StepsCount),
+			CreatedAt:    path.CreatedAt.Time,
+			UpdatedAt:    path.UpdatedAt.Time,
+		}
+	}
+
+	return serializedPaths, http.StatusAccepted, nil
+}
+
+// GetLearningPath returns a path with its ordered steps, and per-step
+// completion when the caller is an app user
+func GetLearningPath(c *gin.Context, pathID uuid.UUID) (dto.LearningPathDTO, int, error) {
+	path, err := db.Queries.GetLearningPathById(c, pathID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LearningPathDTO{}, http.StatusNotFound, fmt.Errorf(
+				"learning path of id %s not found",
+				pathID,
+			)
+		}
+		logger.Log.Errorf("failed to get learning path of id %s, %s", pathID, err.Error())
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	steps, err := db.Queries.GetLearningPathSteps(c, pathID)
+	if err != nil {
+		logger.Log.Errorf("failed to get steps of learning path %s, %s", pathID, err.Error())
+		return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+	}
+
+	progress := map[uuid.UUID]models.GetPlaylistProgressByUserIdRow{}
+	user, ok := middleware.GetUser(c)
+	if ok && user.AppUser != nil && len(steps) > 0 {
+		playlistIDs := make([]uuid.UUID, len(steps))
+		for i, step := range steps {
+			playlistIDs[i] = step.ID
+		}
+
+		progress, err = playlistservice.GetPlaylistProgress(c, user.AppUser.UserID, playlistIDs)
+		if err != nil {
+			logger.Log.Errorf("failed to get progress of user %s, %s", user.AppUser.UserID, err.Error())
+			return dto.LearningPathDTO{}, http.StatusInternalServerError, err
+		}
+	}
+
+	completedSteps := 0
+	serializedSteps := make([]dto.LearningPathStepDTO, len(steps))
+	for i, step := range steps {
+		stepProgress := progress[step.ID]
+		isCompleted := playlistservice.IsPlaylistCompleted(stepProgress)
+		if isCompleted {
+			completedSteps++
+		}
+
+		serializedSteps[i] = dto.LearningPathStepDTO{
+			StepNumber:      int(step.StepNumber),
+			PlaylistID:      step.ID,
+			Name:            step.Name.String,
+			Description:     step.Description.String,
+			Code:            step.Code,
+			ThumbnailURL:    step.ThumbnailUrl.String,
+			AverageRating:   step.AverageRating,
+			RatingCount:     int(step.RatingCount),
+			TotalTopics:     int(stepProgress.TotalTopics),
+			CompletedTopics: int(stepProgress.CompletedTopics),
+			IsCompleted:     isCompleted,
+		}
+	}
+
+	return dto.LearningPathDTO{
+		ID:             path.ID,
+		Name:           path.Name,
+		Description:    path.Description.String,
+		ThumbnailURL:   path.ThumbnailUrl.String,
+		StepsCount:     len(steps),
+		CompletedSteps: completedSteps,
+		CreatedAt:      path.CreatedAt.Time,
+		UpdatedAt:      path.UpdatedAt.Time,
+		Steps:          serializedSteps,
+	}, http.StatusAccepted, nil
+}
+
+func validateStepPlaylists(c *gin.Context, playlistIDs []uuid.UUID) (int, error) {
+	seen := make(map[uuid.UUID]bool, len(playlistIDs))
+	for _, id := range playlistIDs {
+		if seen[id] {
+			return http.StatusBadRequest, fmt.Errorf("playlist %s appears more than once", id)
+		}
+		seen[id] = true
+
+		if _, err := db.Queries.GetPlaylistById(c, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return http.StatusNotFound, fmt.Errorf("playlist of id %s not found", id)
+			}
+			logger.Log.Errorf("failed to get playlist of id %s, %s", id, err.Error())
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+func insertSteps(
+	c *gin.Context,
+	qtx *models.Queries,
+	pathID uuid.UUID,
+	playlistIDs []uuid.UUID,
+	adminID uuid.UUID,
+) error {
+	for i, playlistID := range playlistIDs {
+		err := qtx.CreateLearningPathStep(c, models.CreateLearningPathStepParams{
+			LearningPathID: pathID,
+			PlaylistID:     playlistID,
+			StepNumber:     int32(i + 1),
+			UpdatedBy:      util.GetNullUUID(adminID),
+		})
+		if err != nil {
+			logger.Log.Errorf("failed to insert step %d of learning path %s, %s", i+1, pathID, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package moderationservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+var ErrContentRejected = errors.New("content rejected by moderation")
+
+// Hook inspects user generated content and returns the content to be stored,
+// returning an error wrapping ErrContentRejected if it must not be stored
+type Hook func(ctx context.Context, content string) (string, error)
+
+var (
+	hooksMu sync.RWMutex
+	hooks   = []Hook{trimContent, maskBlockedWords}
+)
+
+// blockedWords caches the regex of the configured blocked words, rebuilt only
+// when the configured list changes
+var blockedWords struct {
+	sync.Mutex
+	source string
+	regex  *regexp.Regexp
+}
+
+// RegisterHook appends a hook that runs after the built-in ones
+func RegisterHook(hook Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// ModerateContent runs every registered hook over the content in order,
+// shared by chat messages and playlist reviews
+func ModerateContent(ctx context.Context, content string) (string, error) {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+
+	var err error
+	for _, hook := range hooks {
+		content, err = hook(ctx, content)
+		if err != nil {
+			return constant.Blank, err
+		}
+	}
+
+	return content, nil
+}
+
+// trimContent only trims the ends, inner whitespace is kept since messages
+// and reviews are rendered as markdown
+func trimContent(_ context.Context, content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == constant.Blank {
+		return constant.Blank, fmt.Errorf("%w: content is empty", ErrContentRejected)
+	}
+	return content, nil
+}
+
+func maskBlockedWords(_ context.Context, content string) (string, error) {
+	wordsRegex := blockedWordsRegex(config.GetConfig().BlockedWords)
+	if wordsRegex == nil {
+		return content, nil
+	}
+
+	return wordsRegex.ReplaceAllStringFunc(content, func(match string) string {
+		return strings.Repeat("*", len([]rune(match)))
+	}), nil
+}
+
+// blockedWordsRegex matches any of the comma separated words as a whole word,
+// nil when no word is blocked
+func blockedWordsRegex(source string) *regexp.Regexp {
+	blockedWords.Lock()
+	defer blockedWords.Unlock()
+
+	if blockedWords.regex != nil && blockedWords.source == source {
+		return blockedWords.regex
+	}
+
+	var words []string
+	for _, word := range strings.Split(source, ",") {
+		word = strings.TrimSpace(word)
+		if word != constant.Blank {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	// go regexps prefer the first alternative, longer words go first so a
+	// blocked word inside another one doesn't mask only part of it
+	sort.Slice(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+
+	blockedWords.source = source
+	blockedWords.regex = regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+	return blockedWords.regex
+}
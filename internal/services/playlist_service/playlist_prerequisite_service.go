@@ -0,0 +1,213 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetPlaylistProgress returns the topic completion of the given playlists for a user,
+// keyed by playlist id. Playlists without topics are absent from the map.
+func GetPlaylistProgress(
+	ctx context.Context,
+	userID uuid.UUID,
+	playlistIDs []uuid.UUID,
+) (map[uuid.UUID]models.GetPlaylistProgressByUserIdRow, error) {
+	progress := make(map[uuid.UUID]models.GetPlaylistProgressByUserIdRow, len(playlistIDs))
+	if len(playlistIDs) == 0 {
+		return progress, nil
+	}
+
+	rows, err := db.Queries.GetPlaylistProgressByUserId(ctx, models.GetPlaylistProgressByUserIdParams{
+		UserID:      userID,
+		PlaylistIds: playlistIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		progress[row.PlaylistID] = row
+	}
+	return progress, nil
+}
+
+// IsPlaylistCompleted reports whether every topic of a playlist has a watched video
+func IsPlaylistCompleted(progress models.GetPlaylistProgressByUserIdRow) bool {
+	return progress.TotalTopics > 0 && progress.CompletedTopics >= progress.TotalTopics
+}
+
+func AddPlaylistPrerequisite(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	prerequisiteID uuid.UUID,
+) ([]dto.PlaylistPrerequisiteDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return nil, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	if playlistID == prerequisiteID {
+		return nil, http.StatusBadRequest, fmt.Errorf("a playlist cannot be its own prerequisite")
+	}
+
+	for _, id := range []uuid.UUID{playlistID, prerequisiteID} {
+		if _, err := db.Queries.GetPlaylistById(c, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, http.StatusNotFound, fmt.Errorf("playlist of id %s not found", id)
+			}
+			logger.Log.Errorf("failed to get playlist of id %s, %s", id, err.Error())
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	// Serialize prerequisite writes so two concurrent links cannot close a cycle together
+	if _, err := tx.ExecContext(c, "LOCK TABLE playlist_prerequisite IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to lock playlist prerequisites, %s", err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Linking playlist -> prerequisite closes a cycle if playlist is already
+	// reachable from the prerequisite through its own prerequisites
+	createsCycle, err := qtx.IsPlaylistPrerequisiteReachable(c, models.IsPlaylistPrerequisiteReachableParams{
+		FromPlaylistID: prerequisiteID,
+		ToPlaylistID:   playlistID,
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to check prerequisite cycle of %s, %s", playlistID, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+	if createsCycle {
+		tx.Rollback()
+		return nil, http.StatusConflict, fmt.Errorf(
+			"playlist %s already depends on %s, linking would create a cycle",
+			prerequisiteID,
+			playlistID,
+		)
+	}
+
+	err = qtx.CreatePlaylistPrerequisite(c, models.CreatePlaylistPrerequisiteParams{
+		PlaylistID:             playlistID,
+		PrerequisitePlaylistID: prerequisiteID,
+		UpdatedBy:              util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to create prerequisite of playlist %s, %s", playlistID, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	prerequisites, statusCode, err := GetPlaylistPrerequisites(c, playlistID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	return prerequisites, http.StatusCreated, nil
+}
+
+func RemovePlaylistPrerequisite(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	prerequisiteID uuid.UUID,
+) (int, error) {
+	deleted, err := db.Queries.DeletePlaylistPrerequisite(c, models.DeletePlaylistPrerequisiteParams{
+		PlaylistID:             playlistID,
+		PrerequisitePlaylistID: prerequisiteID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to delete prerequisite of playlist %s, %s", playlistID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf(
+			"playlist %s is not a prerequisite of %s",
+			prerequisiteID,
+			playlistID,
+		)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// GetPlaylistPrerequisites lists the direct prerequisites of a playlist, with
+// completion filled in when the caller is an app user
+func GetPlaylistPrerequisites(
+	c *gin.Context,
+	playlistID uuid.UUID,
+) ([]dto.PlaylistPrerequisiteDTO, int, error) {
+	prerequisites, err := db.Queries.GetPlaylistPrerequisites(c, playlistID)
+	if err != nil {
+		logger.Log.Errorf("failed to get prerequisites of playlist %s, %s", playlistID, err.Error())
+		return []dto.PlaylistPrerequisiteDTO{}, http.StatusInternalServerError, err
+	}
+
+	progress := map[uuid.UUID]models.GetPlaylistProgressByUserIdRow{}
+	user, ok := middleware.GetUser(c)
+	if ok && user.AppUser != nil && len(prerequisites) > 0 {
+		prerequisiteIDs := make([]uuid.UUID, len(prerequisites))
+		for i, prerequisite := range prerequisites {
+			prerequisiteIDs[i] = prerequisite.ID
+		}
+
+		progress, err = GetPlaylistProgress(c, user.AppUser.UserID, prerequisiteIDs)
+		if err != nil {
+			logger.Log.Errorf("failed to get progress of user %s, %s", user.AppUser.UserID, err.Error())
+			return []dto.PlaylistPrerequisiteDTO{}, http.StatusInternalServerError, err
+		}
+	}
+
+	serializedPrerequisites := make([]dto.PlaylistPrerequisiteDTO, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		playlistProgress := progress[prerequisite.ID]
+		serializedPrerequisites[i] = dto.PlaylistPrerequisiteDTO{
+			ID:              prerequisite.ID,
+			Name:            prerequisite.Name.String,
+			Code:            prerequisite.Code,
+			ThumbnailURL:    prerequisite.ThumbnailUrl.String,
+			TotalTopics:     int(playlistProgress.TotalTopics),
+			CompletedTopics: int(playlistProgress.CompletedTopics),
+			IsCompleted:     IsPlaylistCompleted(playlistProgress),
+		}
+	}
+
+	return serializedPrerequisites, http.StatusAccepted, nil
+}
+
+// getUnmetPrerequisites returns the prerequisites of a playlist the app user has not completed yet
+func getUnmetPrerequisites(c *gin.Context, playlistID uuid.UUID) ([]dto.PlaylistPrerequisiteDTO, error) {
+	prerequisites, _, err := GetPlaylistPrerequisites(c, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	unmet := []dto.PlaylistPrerequisiteDTO{}
+	for _, prerequisite := range prerequisites {
+		if !prerequisite.IsCompleted {
+			unmet = append(unmet, prerequisite)
+		}
+	}
+	return unmet, nil
+}
@@ -0,0 +1,166 @@
+package playlistservice
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	moderationservice "github.com/easc01/mindo-server/internal/services/moderation_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func UpsertPlaylistReview(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	req dto.UpsertPlaylistReviewRequest,
+) (dto.PlaylistReviewDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.PlaylistReviewDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+	userID := user.AppUser.UserID
+
+	if _, err := db.Queries.GetPlaylistById(c, playlistID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.PlaylistReviewDTO{}, http.StatusNotFound, fmt.Errorf(
+				"playlist of id %s not found",
+				playlistID,
+			)
+		}
+		logger.Log.Errorf("failed to get playlist of id %s, %s", playlistID, err.Error())
+		return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	reviewText := strings.TrimSpace(req.Review)
+	if reviewText != constant.Blank {
+		moderatedText, err := moderationservice.ModerateContent(c, reviewText)
+		if err != nil {
+			if errors.Is(err, moderationservice.ErrContentRejected) {
+				return dto.PlaylistReviewDTO{}, http.StatusUnprocessableEntity, err
+			}
+			return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+		}
+		reviewText = moderatedText
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	review, err := qtx.UpsertPlaylistReview(c, models.UpsertPlaylistReviewParams{
+		PlaylistID: playlistID,
+		UserID:     userID,
+		Rating:     int32(req.Rating),
+		Review:     util.GetSQLNullString(reviewText),
+		UpdatedBy:  util.GetNullUUID(userID),
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to upsert review of playlist %s, %s", playlistID, err.Error())
+		return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := qtx.RefreshPlaylistRatingById(c, playlistID); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to refresh rating of playlist %s, %s", playlistID, err.Error())
+		return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	return dto.PlaylistReviewDTO{
+		ID:             review.ID,
+		PlaylistID:     review.PlaylistID,
+		UserID:         review.UserID,
+		Name:           user.AppUser.Name,
+		Username:       user.AppUser.Username,
+		UserProfileUrl: user.AppUser.ProfilePictureUrl,
+		UserColor:      user.AppUser.Color,
+		Rating:         int(review.Rating),
+		Review:         review.Review.String,
+		CreatedAt:      review.CreatedAt.Time,
+		UpdatedAt:      review.UpdatedAt.Time,
+	}, http.StatusCreated, nil
+}
+
+func GetPlaylistReviews(
+	c *gin.Context,
+	playlistID uuid.UUID,
+) ([]dto.PlaylistReviewDTO, int, error) {
+	reviews, err := db.Queries.GetPlaylistReviewsByPlaylistId(c, playlistID)
+	if err != nil {
+		logger.Log.Errorf("failed to get reviews of playlist %s, %s", playlistID, err.Error())
+		return []dto.PlaylistReviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedReviews := make([]dto.PlaylistReviewDTO, len(reviews))
+	for i, review := range reviews {
+		serializedReviews[i] = dto.PlaylistReviewDTO{
+			ID:             review.ID,
+			PlaylistID:     review.PlaylistID,
+			UserID:         review.UserID,
+			Name:           review.Name.String,
+			Username:       review.Username.String,
+			UserProfileUrl: review.ProfilePictureUrl.String,
+			UserColor:      review.Color,
+			Rating:         int(review.Rating),
+			Review:         review.Review.String,
+			CreatedAt:      review.CreatedAt.Time,
+			UpdatedAt:      review.UpdatedAt.Time,
+		}
+	}
+
+	return serializedReviews, http.StatusAccepted, nil
+}
+
+func DeletePlaylistReview(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	reviewID uuid.UUID,
+) (int, error) {
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	_, err = qtx.DeletePlaylistReviewById(c, models.DeletePlaylistReviewByIdParams{
+		ID:         reviewID,
+		PlaylistID: playlistID,
+	})
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return http.StatusNotFound, fmt.Errorf("review of id %s not found", reviewID)
+		}
+		logger.Log.Errorf("failed to delete review %s, %s", reviewID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if err := qtx.RefreshPlaylistRatingById(c, playlistID); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to refresh rating of playlist %s, %s", playlistID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
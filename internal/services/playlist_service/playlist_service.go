@@ -6,8 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/easc01/mindo-server/internal/config"
 	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
 	playlistrepository "github.com/easc01/mindo-server/internal/repository/playlist_repository"
@@ -25,6 +28,15 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	SortByRelevance   = ""
+	SortByRating      = "rating"
+	SortByRatingCount = "ratingCount"
+	SortByTrending    = "trending"
+)
+
+var PlaylistSortOptions = []string{SortByRelevance, SortByRating, SortByRatingCount, SortByTrending}
+
 func serializeTopics(topics *[]models.Topic) *[]dto.TopicsMiniDTO {
 	var serializedTopics []dto.TopicsMiniDTO
 
@@ -40,11 +52,24 @@ func serializeTopics(topics *[]models.Topic) *[]dto.TopicsMiniDTO {
 	return &serializedTopics
 }
 
+// topicsInsertedHook runs inside the playlist creation transaction once the
+// topics are inserted, letting callers persist extra rows against them
+type topicsInsertedHook func(tx *sql.Tx, topics []models.Topic) (int, error)
+
 func ProcessPlaylistCreation(
 	c *gin.Context,
 	req dto.CreatePlaylistRequest,
 	userId uuid.UUID,
 ) (dto.PlaylistDetailsDTO, int, error) {
+	return processPlaylistCreation(c, req, userId, nil)
+}
+
+func processPlaylistCreation(
+	c *gin.Context,
+	req dto.CreatePlaylistRequest,
+	userId uuid.UUID,
+	onTopicsInserted topicsInsertedHook,
+) (dto.PlaylistDetailsDTO, int, error) {
 
 	// Begin a new transaction
 	tx, err := db.DB.BeginTx(c, nil)
@@ -94,22 +119,31 @@ func ProcessPlaylistCreation(
 		return dto.PlaylistDetailsDTO{}, statusCode, err
 	}
 
+	if onTopicsInserted != nil {
+		statusCode, err = onTopicsInserted(tx, topics)
+		if err != nil {
+			return dto.PlaylistDetailsDTO{}, statusCode, err
+		}
+	}
+
 	// Serialize topics for the response
 	serializedTopics := serializeTopics(&topics)
 
 	return dto.PlaylistDetailsDTO{
-		ID:           playlist.ID.String(),
-		Name:         playlist.Name.String,
-		Description:  playlist.Description.String,
-		InterestID:   playlist.InterestID.UUID.String(),
-		ThumbnailURL: playlist.ThumbnailUrl.String,
-		Views:        int(playlist.Views.Int32),
-		Code:         playlist.Code,
-		CreatedAt:    playlist.CreatedAt.Time,
-		UpdatedAt:    playlist.UpdatedAt.Time,
-		UpdatedBy:    playlist.UpdatedBy.UUID.String(),
-		IsAIGen:      playlist.IsAiGen,
-		Topics:       *serializedTopics,
+		ID:            playlist.ID.String(),
+		Name:          playlist.Name.String,
+		Description:   playlist.Description.String,
+		InterestID:    playlist.InterestID.UUID.String(),
+		ThumbnailURL:  playlist.ThumbnailUrl.String,
+		Views:         int(playlist.Views.Int32),
+		AverageRating: playlist.AverageRating,
+		RatingCount:   int(playlist.RatingCount),
+		Code:          playlist.Code,
+		CreatedAt:     playlist.CreatedAt.Time,
+		UpdatedAt:     playlist.UpdatedAt.Time,
+		UpdatedBy:     playlist.UpdatedBy.UUID.String(),
+		IsAIGen:       playlist.IsAiGen,
+		Topics:        *serializedTopics,
 	}, http.StatusCreated, nil
 }
 
@@ -230,21 +264,26 @@ func GetPlaylistWithTopics(
 	}
 
 	// Clone necessary data (user)
+	var unmetPrerequisites []dto.PlaylistPrerequisiteDTO
 	user, ok := middleware.GetUser(c)
 	if ok && user.AppUser != nil {
+		// Missing prerequisites only warn, they never block the playlist
+		unmetPrerequisites, err = getUnmetPrerequisites(c, playlistID)
+		if err != nil {
+			logger.Log.Errorf("failed to get unmet prerequisites of playlist %s, %s", playlistID, err.Error())
+		}
+
 		appUser := user.AppUser
 		go func(appUserID uuid.UUID, playlistID uuid.UUID) {
 			ctx := context.Background()
 
-			// Update views
-			if err := db.Queries.UpdatePlaylistViewCountById(ctx, models.UpdatePlaylistViewCountByIdParams{
-				ID: playlistID,
-				Views: sql.NullInt32{
-					Int32: 1,
-					Valid: true,
-				},
+			// Record a view, deduplicated per user per window
+			if err := db.Queries.CreatePlaylistView(ctx, models.CreatePlaylistViewParams{
+				PlaylistID:  playlistID,
+				UserID:      appUserID,
+				WindowStart: time.Now().UTC().Truncate(config.GetConfig().PlaylistViewWindow),
 			}); err != nil {
-				logger.Log.Errorf("failed to update playlist views: %v", err)
+				logger.Log.Errorf("failed to record playlist view: %v", err)
 			}
 
 			// Create user_playlist
@@ -260,25 +299,41 @@ func GetPlaylistWithTopics(
 
 	// Return the playlist with topics
 	return dto.PlaylistDetailsDTO{
-		ID:           playlist.ID.String(),
-		Name:         playlist.Name.String,
-		Description:  playlist.Description.String,
-		Code:         playlist.Code,
-		ThumbnailURL: playlist.ThumbnailUrl.String,
-		Views:        int(playlist.Views.Int32),
-		CreatedAt:    playlist.CreatedAt.Time,
-		UpdatedAt:    playlist.UpdatedAt.Time,
-		UpdatedBy:    playlist.UpdatedBy.UUID.String(),
-		IsAIGen:      playlist.IsAIGen,
-		Topics:       playlist.Topics,
+		ID:            playlist.ID.String(),
+		Name:          playlist.Name.String,
+		Description:   playlist.Description.String,
+		Code:          playlist.Code,
+		ThumbnailURL:  playlist.ThumbnailUrl.String,
+		Views:         int(playlist.Views.Int32),
+		AverageRating: playlist.AverageRating,
+		RatingCount:   int(playlist.RatingCount),
+		CreatedAt:     playlist.CreatedAt.Time,
+		UpdatedAt:     playlist.UpdatedAt.Time,
+		UpdatedBy:     playlist.UpdatedBy.UUID.String(),
+		IsAIGen:       playlist.IsAIGen,
+		Topics:        playlist.Topics,
+
+		UnmetPrerequisites: unmetPrerequisites,
 	}, http.StatusAccepted, nil
 }
 
 func GetAllPlaylistPreviews(
 	c *gin.Context,
 	searchTag string,
+	sortBy string,
 ) ([]dto.PlaylistPreviewDTO, int, error) {
-	playlists, err := db.Queries.GetAllPlaylistsPreviews(c, searchTag)
+	if !slices.Contains(PlaylistSortOptions, sortBy) {
+		return []dto.PlaylistPreviewDTO{}, http.StatusBadRequest, fmt.Errorf(
+			"invalid sortBy %s, allowed values are %s",
+			sortBy,
+			strings.Join(PlaylistSortOptions[1:], ", "),
+		)
+	}
+
+	playlists, err := db.Queries.GetAllPlaylistsPreviews(c, models.GetAllPlaylistsPreviewsParams{
+		SearchTag: searchTag,
+		SortBy:    sortBy,
+	})
 
 	if err != nil {
 		logger.Log.Error("failed to get playlist previews")
@@ -292,18 +347,23 @@ func GetAllPlaylistPreviews(
 	var serializedPlaylist []dto.PlaylistPreviewDTO
 	for _, playlist := range playlists {
 		serializedPlaylist = append(serializedPlaylist, dto.PlaylistPreviewDTO{
-			ID:           playlist.ID.String(),
-			Name:         playlist.Name.String,
-			Description:  playlist.Description.String,
-			InterestID:   playlist.InterestID.UUID.String(),
-			ThumbnailURL: playlist.ThumbnailUrl.String,
-			Views:        int(playlist.Views.Int32),
-			Code:         playlist.Code,
-			CreatedAt:    playlist.CreatedAt.Time,
-			UpdatedAt:    playlist.UpdatedAt.Time,
-			UpdatedBy:    playlist.UpdatedBy.UUID.String(),
-			IsAIGen:      playlist.IsAiGen,
-			TopicsCount:  int(playlist.TopicsCount.(int64)),
+			ID:            playlist.ID.String(),
+			Name:          playlist.Name.String,
+			Description:   playlist.Description.String,
+			InterestID:    playlist.InterestID.UUID.String(),
+			ThumbnailURL:  playlist.ThumbnailUrl.String,
+			Views:         int(playlist.Views.Int32),
+			AverageRating: playlist.AverageRating,
+			RatingCount:   int(playlist.RatingCount),
+			Views24h:      int(playlist.Views24h),
+			Views7d:       int(playlist.Views7d),
+			TrendingScore: playlist.TrendingScore,
+			Code:          playlist.Code,
+			CreatedAt:     playlist.CreatedAt.Time,
+			UpdatedAt:     playlist.UpdatedAt.Time,
+			UpdatedBy:     playlist.UpdatedBy.UUID.String(),
+			IsAIGen:       playlist.IsAiGen,
+			TopicsCount:   int(playlist.TopicsCount.(int64)),
 		})
 	}
 
@@ -316,53 +376,98 @@ func GetVideosByTopicId(
 	videoId string,
 ) (dto.GroupedVideoDataResponse, int, error) {
 	topic, err := topicrepository.GetTopicByIDWithVideos(c, topicId)
-	videos := topic.Videos
 
 	if err != nil {
 		logger.Log.Errorf("failed to get yt videos by topic id %s, %s", topicId, err.Error())
 		return dto.GroupedVideoDataResponse{}, http.StatusInternalServerError, err
 	}
 
-	// no videos found in db, search and save new ones
+	videos, err := removeUnavailableVideos(c, topic.Videos)
+	if err != nil {
+		logger.Log.Errorf("failed to get video bans, %s", err.Error())
+		return dto.GroupedVideoDataResponse{}, http.StatusInternalServerError, err
+	}
+
+	// no videos found in db, search and save new ones, concurrent requests
+	// for the same topic share a single fetch
 	if len(videos) == 0 {
-		newVideos, err := FetchAndSaveNewVideos(c, topic, topic.PlaylistName.String)
+		user, ok := middleware.GetUser(c)
+		if !ok {
+			return dto.GroupedVideoDataResponse{}, http.StatusUnauthorized, fmt.Errorf(message.NullUserContext)
+		}
+
+		var userID uuid.UUID
+		if user.AppUser != nil {
+			userID = user.AppUser.UserID
+		} else {
+			userID = user.AdminUser.UserID
+		}
+
+		// waiters share this fetch, so it must outlive the request that started it
+		ctx := context.WithoutCancel(c)
+		newVideos, err, _ := topicVideoFetches.Do(topicId.String(), func() ([]dto.VideoDataDTO, error) {
+			return fetchMissingTopicVideos(ctx, topicId, userID)
+		})
+		if errors.Is(err, youtubeservice.ErrQuotaExhausted) {
+			return dto.GroupedVideoDataResponse{Status: VideoStatusComingSoon}, http.StatusAccepted, nil
+		}
 		if err != nil {
 			return dto.GroupedVideoDataResponse{}, http.StatusInternalServerError, err
 		}
-		return GroupVideos(newVideos, videoId), http.StatusCreated, nil
+		return GroupVideos(withViewerProgress(c, newVideos), videoId), http.StatusCreated, nil
+	}
+
+	return GroupVideos(withViewerProgress(c, videos), videoId), http.StatusAccepted, nil
+}
+
+// VideoStatusComingSoon marks a topic without videos whose fetch waits for quota
+const VideoStatusComingSoon = "coming_soon"
+
+var topicVideoFetches util.Coalescer[[]dto.VideoDataDTO]
+
+// fetchMissingTopicVideos re-reads the topic before searching, a fetch that
+// finished just before this one started has already saved its videos
+func fetchMissingTopicVideos(ctx context.Context, topicId uuid.UUID, userID uuid.UUID) ([]dto.VideoDataDTO, error) {
+	topic, err := topicrepository.GetTopicByIDWithVideos(ctx, topicId)
+	if err != nil {
+		logger.Log.Errorf("failed to get yt videos by topic id %s, %s", topicId, err.Error())
+		return []dto.VideoDataDTO{}, err
+	}
+
+	videos, err := removeUnavailableVideos(ctx, topic.Videos)
+	if err != nil {
+		return []dto.VideoDataDTO{}, err
 	}
 
-	return GroupVideos(videos, videoId), http.StatusAccepted, nil
+	if len(videos) > 0 {
+		return videos, nil
+	}
+
+	savedVideos, err := FetchAndSaveNewVideos(ctx, topic, topic.PlaylistName.String, userID)
+	if err != nil {
+		return []dto.VideoDataDTO{}, err
+	}
+
+	// a search can find a video learners already got hidden, it stays hidden
+	return slices.DeleteFunc(savedVideos, func(video dto.VideoDataDTO) bool {
+		return video.IsHidden
+	}), nil
 }
 
 func FetchAndSaveNewVideos(
-	c *gin.Context,
+	ctx context.Context,
 	topic topicrepository.GetTopicByIDWithVideosRow,
 	playlistName string,
+	userID uuid.UUID,
 ) ([]dto.VideoDataDTO, error) {
 
 	topicId := topic.ID
 	topicName := topic.Name.String
-	user, ok := middleware.GetUser(c)
 
-	if !ok {
-		return []dto.VideoDataDTO{}, fmt.Errorf(message.NullUserContext)
-	}
-
-	var userID uuid.UUID
-	if user.AppUser != nil {
-		userID = user.AppUser.UserID
-	} else {
-		userID = user.AdminUser.UserID
-	}
-
-	videos, err := youtubeservice.SearchVideosByTopic(
-		fmt.Sprintf("%s in %s", topicName, playlistName),
-		10,
-	)
+	videos, err := searchTopicVideos(ctx, topicName, playlistName)
 
 	if err != nil {
-		logger.Log.Errorf("failed to search %s on youtube, %s", topicName, err.Error())
+		logger.Log.Errorf("failed to search videos of %s, %s", topicName, err.Error())
 		return []dto.VideoDataDTO{}, err
 	}
 
@@ -392,8 +497,20 @@ func GroupVideos(videos []dto.VideoDataDTO, videoID string) dto.GroupedVideoData
 		}
 	}
 
+	// without a requested video the pinned one leads, then the best ranked
 	if firstVideo == nil {
 		firstVideo = &videos[0]
+		for i := range videos {
+			if videos[i].IsPinned != firstVideo.IsPinned {
+				if videos[i].IsPinned {
+					firstVideo = &videos[i]
+				}
+				continue
+			}
+			if videos[i].RankScore > firstVideo.RankScore {
+				firstVideo = &videos[i]
+			}
+		}
 	}
 
 	result.Video = *firstVideo
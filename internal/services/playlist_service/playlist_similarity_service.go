@@ -0,0 +1,88 @@
+package playlistservice
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	similarTopicWeight        = 0.5
+	similarCoOccurrenceWeight = 0.3
+	similarInterestWeight     = 0.2
+	maxSimilarPerPlaylist     = 20
+)
+
+// RefreshPlaylistSimilarities rebuilds the precomputed similar playlists
+// from topic name overlap, shared interest and user_playlist co-occurrence
+func RefreshPlaylistSimilarities(ctx context.Context) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.DeletePlaylistSimilarities(ctx); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to clear playlist similarities, %s", err.Error())
+		return err
+	}
+
+	err = qtx.RefreshPlaylistSimilarities(ctx, models.RefreshPlaylistSimilaritiesParams{
+		TopicWeight:        similarTopicWeight,
+		InterestWeight:     similarInterestWeight,
+		CoOccurrenceWeight: similarCoOccurrenceWeight,
+		MaxPerPlaylist:     maxSimilarPerPlaylist,
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to refresh playlist similarities, %s", err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func GetSimilarPlaylists(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	limit int,
+) ([]dto.PlaylistPreviewDTO, int, error) {
+	playlists, err := db.Queries.GetSimilarPlaylists(c, models.GetSimilarPlaylistsParams{
+		PlaylistID: playlistID,
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get similar playlists of %s, %s", playlistID, err.Error())
+		return []dto.PlaylistPreviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedPlaylists := make([]dto.PlaylistPreviewDTO, len(playlists))
+	for i, playlist := range playlists {
+		serializedPlaylists[i] = dto.PlaylistPreviewDTO{
+			ID:              playlist.ID.String(),
+			Name:            playlist.Name.String,
+			Description:     playlist.Description.String,
+			InterestID:      playlist.InterestID.UUID.String(),
+			ThumbnailURL:    playlist.ThumbnailUrl.String,
+			Views:           int(playlist.Views.Int32),
+			AverageRating:   playlist.AverageRating,
+			RatingCount:     int(playlist.RatingCount),
+			SimilarityScore: playlist.Score,
+			Code:            playlist.Code,
+			CreatedAt:       playlist.CreatedAt.Time,
+			UpdatedAt:       playlist.UpdatedAt.Time,
+			UpdatedBy:       playlist.UpdatedBy.UUID.String(),
+			IsAIGen:         playlist.IsAiGen,
+			TopicsCount:     int(playlist.TopicsCount),
+		}
+	}
+
+	return serializedPlaylists, http.StatusAccepted, nil
+}
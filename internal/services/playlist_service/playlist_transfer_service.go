@@ -0,0 +1,879 @@
+package playlistservice
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/models"
+	playlistrepository "github.com/easc01/mindo-server/internal/repository/playlist_repository"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "md"
+	FormatCSV      = "csv"
+
+	PlaylistBundleVersion = 1
+)
+
+var importableVideoProviders = []string{
+	constant.VideoProviderYoutube,
+	constant.VideoProviderPeerTube,
+	constant.VideoProviderFixture,
+}
+
+// importedVideoProvider defaults videos without a provider, as in markdown and csv, to youtube
+func importedVideoProvider(provider string) string {
+	if provider == constant.Blank {
+		return constant.VideoProviderYoutube
+	}
+	return provider
+}
+
+const (
+	videoFlagPinned = "pinned"
+	videoFlagManual = "manual"
+)
+
+var csvHeader = []string{
+	"name",
+	"description",
+	"domain",
+	"thumbnail_url",
+	"is_ai_gen",
+	"topic",
+	"video_id",
+	"video_title",
+	"video_channel",
+	"video_thumbnail_url",
+	"video_provider",
+	"video_pinned",
+	"video_manual",
+	"material_title",
+	"material_content",
+}
+
+// ParsePlaylistImport decodes a playlist in the given format, collecting
+// every validation error instead of stopping at the first one
+func ParsePlaylistImport(
+	format string,
+	body []byte,
+) (dto.PlaylistBundle, []dto.PlaylistImportError) {
+	var bundle dto.PlaylistBundle
+	var errs []dto.PlaylistImportError
+
+	switch format {
+	case FormatJSON:
+		bundle, errs = parseJSONBundle(body)
+	case FormatMarkdown:
+		bundle, errs = parseMarkdownOutline(body)
+	case FormatCSV:
+		bundle, errs = parseCSVSheet(body)
+	default:
+		return bundle, []dto.PlaylistImportError{{
+			Field:   "format",
+			Message: fmt.Sprintf("unsupported format %q, use json, md or csv", format),
+		}}
+	}
+
+	if len(errs) > 0 {
+		return bundle, errs
+	}
+
+	return bundle, validatePlaylistBundle(bundle)
+}
+
+func validatePlaylistBundle(bundle dto.PlaylistBundle) []dto.PlaylistImportError {
+	var errs []dto.PlaylistImportError
+	playlist := bundle.Playlist
+
+	if strings.TrimSpace(playlist.Name) == constant.Blank {
+		errs = append(errs, dto.PlaylistImportError{Field: "playlist.name", Message: "name is required"})
+	}
+
+	if strings.TrimSpace(playlist.DomainName) == constant.Blank {
+		errs = append(errs, dto.PlaylistImportError{Field: "playlist.domainName", Message: "domain is required"})
+	}
+
+	if len(playlist.Topics) == 0 {
+		errs = append(errs, dto.PlaylistImportError{Field: "playlist.topics", Message: "at least one topic is required"})
+	}
+
+	for i, topic := range playlist.Topics {
+		if strings.TrimSpace(topic.Name) == constant.Blank {
+			errs = append(errs, dto.PlaylistImportError{
+				Field:   fmt.Sprintf("playlist.topics[%d].name", i),
+				Message: "topic name is required",
+			})
+		}
+
+		pinned := 0
+		for j, video := range topic.Videos {
+			if video.Pinned {
+				pinned++
+			}
+
+			if strings.TrimSpace(video.VideoID) == constant.Blank {
+				errs = append(errs, dto.PlaylistImportError{
+					Field:   fmt.Sprintf("playlist.topics[%d].videos[%d].videoId", i, j),
+					Message: "video id is required",
+				})
+			}
+
+			if video.Provider != constant.Blank && !slices.Contains(importableVideoProviders, video.Provider) {
+				errs = append(errs, dto.PlaylistImportError{
+					Field:   fmt.Sprintf("playlist.topics[%d].videos[%d].provider", i, j),
+					Message: fmt.Sprintf("unknown video provider %s", video.Provider),
+				})
+			}
+		}
+
+		if pinned > 1 {
+			errs = append(errs, dto.PlaylistImportError{
+				Field:   fmt.Sprintf("playlist.topics[%d].videos", i),
+				Message: "a topic can pin only one video",
+			})
+		}
+	}
+
+	return errs
+}
+
+func parseJSONBundle(body []byte) (dto.PlaylistBundle, []dto.PlaylistImportError) {
+	var bundle dto.PlaylistBundle
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&bundle); err != nil {
+		importErr := dto.PlaylistImportError{Message: err.Error()}
+
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			importErr.Line = lineAtOffset(body, syntaxErr.Offset)
+		case errors.As(err, &typeErr):
+			importErr.Line = lineAtOffset(body, typeErr.Offset)
+			importErr.Field = typeErr.Field
+		}
+
+		return bundle, []dto.PlaylistImportError{importErr}
+	}
+
+	if bundle.Version != PlaylistBundleVersion {
+		return bundle, []dto.PlaylistImportError{{
+			Field:   "version",
+			Message: fmt.Sprintf("unsupported bundle version %d, expected %d", bundle.Version, PlaylistBundleVersion),
+		}}
+	}
+
+	return bundle, nil
+}
+
+func lineAtOffset(body []byte, offset int64) int {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+	return bytes.Count(body[:offset], []byte("\n")) + 1
+}
+
+// parseMarkdownOutline reads the outline produced by the markdown export:
+//
+//	# Playlist name
+//	Domain: Web Development
+//	Thumbnail: https://...
+//	description lines...
+//
+//	## 1. Topic name
+//	Video: videoId | title | channel | thumbnail url | provider | pinned, manual
+//	### Material: title
+//	~~~markdown
+//	notes...
+//	~~~
+//
+// a material fence closes on a line of at least as many ~ as it opened with,
+// so notes holding ~~~ fences of their own use a longer one
+func parseMarkdownOutline(body []byte) (dto.PlaylistBundle, []dto.PlaylistImportError) {
+	bundle := dto.PlaylistBundle{Version: PlaylistBundleVersion}
+	var errs []dto.PlaylistImportError
+	var description []string
+	var topic *dto.PlaylistBundleTopic
+	var material *dto.PlaylistBundleMaterial
+	var materialLines []string
+	fenceLine := 0
+	fenceLength := 0
+	titleSeen := false
+
+	flushTopic := func() {
+		if topic != nil {
+			bundle.Playlist.Topics = append(bundle.Playlist.Topics, *topic)
+			topic = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+
+		// inside a fenced material block everything is content
+		if fenceLine > 0 {
+			if len(line) >= fenceLength && strings.Trim(line, "~") == constant.Blank {
+				material.Content = strings.Join(materialLines, "\n")
+				topic.StudyMaterial = material
+				material, materialLines, fenceLine, fenceLength = nil, nil, 0, 0
+				continue
+			}
+			materialLines = append(materialLines, strings.TrimRight(raw, " \t"))
+			continue
+		}
+
+		if line == constant.Blank {
+			if topic == nil && titleSeen {
+				description = append(description, constant.Blank)
+			}
+			continue
+		}
+
+		switch {
+		case !titleSeen:
+			if !strings.HasPrefix(line, "# ") {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo,
+					Message: "outline must start with '# <playlist name>'",
+				})
+				return bundle, errs
+			}
+			bundle.Playlist.Name = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			titleSeen = true
+
+		case strings.HasPrefix(line, "## "):
+			flushTopic()
+			name := stripTopicNumber(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+			if name == constant.Blank {
+				errs = append(errs, dto.PlaylistImportError{Line: lineNo, Message: "topic name is required"})
+			}
+			topic = &dto.PlaylistBundleTopic{
+				Name:        name,
+				TopicNumber: len(bundle.Playlist.Topics) + 1,
+			}
+
+		case topic == nil:
+			if key, value, ok := splitMarkdownField(line); ok {
+				switch key {
+				case "domain":
+					bundle.Playlist.DomainName = value
+					continue
+				case "thumbnail":
+					bundle.Playlist.ThumbnailURL = value
+					continue
+				case "ai generated":
+					isAIGen, err := strconv.ParseBool(value)
+					if err != nil {
+						errs = append(errs, dto.PlaylistImportError{
+							Line:    lineNo,
+							Message: fmt.Sprintf("invalid ai generated flag %q", value),
+						})
+					}
+					bundle.Playlist.IsAIGen = isAIGen
+					continue
+				}
+			}
+			description = append(description, line)
+
+		case strings.HasPrefix(strings.ToLower(line), "### material:"):
+			if topic.StudyMaterial != nil {
+				errs = append(errs, dto.PlaylistImportError{Line: lineNo, Message: "topic already has study material"})
+			}
+			material = &dto.PlaylistBundleMaterial{
+				Title: strings.TrimSpace(line[len("### material:"):]),
+			}
+			if i+1 >= len(lines) || tildeFenceLength(strings.TrimSpace(lines[i+1])) == 0 {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo + 1,
+					Message: "study material must be wrapped in a ~~~ fence",
+				})
+				material = nil
+			}
+
+		case tildeFenceLength(line) > 0 && material != nil:
+			fenceLine = lineNo
+			fenceLength = tildeFenceLength(line)
+
+		default:
+			key, value, ok := splitMarkdownField(line)
+			if !ok || key != "video" {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo,
+					Message: fmt.Sprintf("unexpected line %q in topic %q", line, topic.Name),
+				})
+				continue
+			}
+
+			video, err := parseMarkdownVideo(value)
+			if err != nil {
+				errs = append(errs, dto.PlaylistImportError{Line: lineNo, Message: err.Error()})
+				continue
+			}
+			topic.Videos = append(topic.Videos, video)
+		}
+	}
+
+	if fenceLine > 0 {
+		errs = append(errs, dto.PlaylistImportError{Line: fenceLine, Message: "unterminated ~~~ fence"})
+	}
+
+	if !titleSeen {
+		errs = append(errs, dto.PlaylistImportError{Line: 1, Message: "outline is empty"})
+	}
+
+	flushTopic()
+	bundle.Playlist.Description = strings.TrimSpace(strings.Join(description, "\n"))
+
+	return bundle, errs
+}
+
+func splitMarkdownField(line string) (string, string, bool) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return constant.Blank, constant.Blank, false
+	}
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+func stripTopicNumber(name string) string {
+	prefix, rest, ok := strings.Cut(name, ". ")
+	if !ok {
+		return name
+	}
+	if _, err := strconv.Atoi(prefix); err != nil {
+		return name
+	}
+	return strings.TrimSpace(rest)
+}
+
+// tildeFenceLength is how many ~ a line opening a fence starts with, 0 for
+// lines that open none
+func tildeFenceLength(line string) int {
+	length := len(line) - len(strings.TrimLeft(line, "~"))
+	if length < 3 {
+		return 0
+	}
+	return length
+}
+
+// longestTildeRun is the longest run of ~ in the content, the fence around it
+// must be longer
+func longestTildeRun(content string) int {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '~' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// parseMarkdownVideo reads a video line, fields after the thumbnail are
+// optional and a | inside a field is written as \|
+func parseMarkdownVideo(value string) (dto.PlaylistBundleVideo, error) {
+	parts := splitMarkdownVideo(value)
+
+	if parts[0] == constant.Blank {
+		return dto.PlaylistBundleVideo{}, fmt.Errorf("video id is required")
+	}
+	if len(parts) > 6 {
+		return dto.PlaylistBundleVideo{}, fmt.Errorf("video line has more than 6 fields")
+	}
+
+	video := dto.PlaylistBundleVideo{VideoMiniDTO: dto.VideoMiniDTO{VideoID: parts[0]}}
+	if len(parts) > 1 {
+		video.Title = parts[1]
+	}
+	if len(parts) > 2 {
+		video.ChannelTitle = parts[2]
+	}
+	if len(parts) > 3 {
+		video.ThumbnailURL = parts[3]
+	}
+	if len(parts) > 4 {
+		video.Provider = parts[4]
+	}
+	if len(parts) > 5 {
+		for _, flag := range strings.Split(parts[5], ",") {
+			switch strings.ToLower(strings.TrimSpace(flag)) {
+			case videoFlagPinned:
+				video.Pinned = true
+			case videoFlagManual:
+				video.Manual = true
+			case constant.Blank:
+			default:
+				return dto.PlaylistBundleVideo{}, fmt.Errorf("unknown video flag %q", strings.TrimSpace(flag))
+			}
+		}
+	}
+
+	return video, nil
+}
+
+// splitMarkdownVideo splits a video line on |, leaving escaped ones in the
+// field they belong to
+func splitMarkdownVideo(value string) []string {
+	var parts []string
+	var part strings.Builder
+
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '|' || runes[i+1] == '\\'):
+			i++
+			part.WriteRune(runes[i])
+		case runes[i] == '|':
+			parts = append(parts, strings.TrimSpace(part.String()))
+			part.Reset()
+		default:
+			part.WriteRune(runes[i])
+		}
+	}
+
+	return append(parts, strings.TrimSpace(part.String()))
+}
+
+var markdownFieldEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+
+// parseCSVSheet reads one topic per row, playlist columns only need to be
+// filled on the first row; consecutive rows with the same topic add videos
+func parseCSVSheet(body []byte) (dto.PlaylistBundle, []dto.PlaylistImportError) {
+	bundle := dto.PlaylistBundle{Version: PlaylistBundleVersion}
+	var errs []dto.PlaylistImportError
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return bundle, []dto.PlaylistImportError{{Line: 1, Message: fmt.Sprintf("failed to read header, %s", err)}}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, required := range []string{"name", "topic"} {
+		if _, ok := columns[required]; !ok {
+			errs = append(errs, dto.PlaylistImportError{
+				Line:    1,
+				Message: fmt.Sprintf("missing required column %q", required),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return bundle, errs
+	}
+
+	playlistFields := map[string]*string{
+		"name":          &bundle.Playlist.Name,
+		"description":   &bundle.Playlist.Description,
+		"domain":        &bundle.Playlist.DomainName,
+		"thumbnail_url": &bundle.Playlist.ThumbnailURL,
+	}
+
+	var aiGenValue string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			importErr := dto.PlaylistImportError{Message: err.Error()}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				importErr.Line = parseErr.Line
+			}
+			errs = append(errs, importErr)
+			continue
+		}
+
+		lineNo, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			if idx, ok := columns[column]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return constant.Blank
+		}
+
+		for column, field := range playlistFields {
+			value := get(column)
+			if value == constant.Blank {
+				continue
+			}
+			if *field != constant.Blank && *field != value {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo,
+					Field:   column,
+					Message: fmt.Sprintf("conflicts with earlier value %q", *field),
+				})
+				continue
+			}
+			*field = value
+		}
+
+		if value := get("is_ai_gen"); value != constant.Blank {
+			isAIGen, err := strconv.ParseBool(value)
+			if err != nil || (aiGenValue != constant.Blank && aiGenValue != value) {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo,
+					Field:   "is_ai_gen",
+					Message: fmt.Sprintf("invalid ai generated flag %q", value),
+				})
+			} else {
+				aiGenValue = value
+				bundle.Playlist.IsAIGen = isAIGen
+			}
+		}
+
+		topicName := get("topic")
+		if topicName == constant.Blank {
+			errs = append(errs, dto.PlaylistImportError{Line: lineNo, Field: "topic", Message: "topic name is required"})
+			continue
+		}
+
+		topics := bundle.Playlist.Topics
+		var topic *dto.PlaylistBundleTopic
+		if len(topics) > 0 && topics[len(topics)-1].Name == topicName {
+			topic = &topics[len(topics)-1]
+		} else {
+			bundle.Playlist.Topics = append(topics, dto.PlaylistBundleTopic{
+				Name:        topicName,
+				TopicNumber: len(topics) + 1,
+			})
+			topic = &bundle.Playlist.Topics[len(bundle.Playlist.Topics)-1]
+		}
+
+		if videoID := get("video_id"); videoID != constant.Blank {
+			video := dto.PlaylistBundleVideo{
+				VideoMiniDTO: dto.VideoMiniDTO{
+					VideoID:      videoID,
+					Provider:     get("video_provider"),
+					Title:        get("video_title"),
+					ChannelTitle: get("video_channel"),
+					ThumbnailURL: get("video_thumbnail_url"),
+				},
+			}
+
+			readFlag := func(column string) bool {
+				value := get(column)
+				if value == constant.Blank {
+					return false
+				}
+				parsed, err := strconv.ParseBool(value)
+				if err != nil {
+					errs = append(errs, dto.PlaylistImportError{
+						Line:    lineNo,
+						Field:   column,
+						Message: fmt.Sprintf("invalid flag %q", value),
+					})
+				}
+				return parsed
+			}
+			video.Pinned = readFlag("video_pinned")
+			video.Manual = readFlag("video_manual")
+
+			topic.Videos = append(topic.Videos, video)
+		} else if get("video_title") != constant.Blank {
+			errs = append(errs, dto.PlaylistImportError{Line: lineNo, Field: "video_id", Message: "video id is required"})
+		}
+
+		materialTitle, materialContent := get("material_title"), get("material_content")
+		if materialTitle != constant.Blank || materialContent != constant.Blank {
+			if topic.StudyMaterial != nil {
+				errs = append(errs, dto.PlaylistImportError{
+					Line:    lineNo,
+					Field:   "material_title",
+					Message: "topic already has study material",
+				})
+				continue
+			}
+			topic.StudyMaterial = &dto.PlaylistBundleMaterial{
+				Title:   materialTitle,
+				Content: materialContent,
+			}
+		}
+	}
+
+	return bundle, errs
+}
+
+func ProcessPlaylistImport(
+	c *gin.Context,
+	bundle dto.PlaylistBundle,
+	userId uuid.UUID,
+) (dto.PlaylistDetailsDTO, int, error) {
+	topicNames := make([]string, len(bundle.Playlist.Topics))
+	for i, topic := range bundle.Playlist.Topics {
+		topicNames[i] = strings.TrimSpace(topic.Name)
+	}
+
+	req := dto.CreatePlaylistRequest{
+		Name:         bundle.Playlist.Name,
+		Description:  bundle.Playlist.Description,
+		DomainName:   bundle.Playlist.DomainName,
+		ThumbnailURL: bundle.Playlist.ThumbnailURL,
+		IsAIGen:      bundle.Playlist.IsAIGen,
+		Topics:       topicNames,
+	}
+
+	return processPlaylistCreation(c, req, userId, func(tx *sql.Tx, topics []models.Topic) (int, error) {
+		qtx := db.Queries.WithTx(tx)
+		expiry := time.Now().Add(time.Hour * 24)
+
+		for _, topic := range topics {
+			// topic numbers are assigned from the bundle order starting at 1
+			imported := bundle.Playlist.Topics[topic.Number.Int32-1]
+
+			if imported.StudyMaterial != nil {
+				_, err := qtx.UpsertStudyMaterialByTopicId(c, models.UpsertStudyMaterialByTopicIdParams{
+					TopicID:     topic.ID,
+					Title:       util.GetSQLNullString(imported.StudyMaterial.Title),
+					Content:     util.GetSQLNullString(imported.StudyMaterial.Content),
+					ContentText: util.GetSQLNullString(contentutil.PlainText(imported.StudyMaterial.Content)),
+					UpdatedBy:   util.GetNullUUID(userId),
+				})
+				if err != nil {
+					logger.Log.Errorf("failed to import study material of topic %s, %s", topic.ID, err.Error())
+					return http.StatusInternalServerError, err
+				}
+			}
+
+			for _, video := range imported.Videos {
+				_, err := qtx.UpsertYoutubeVideo(c, models.UpsertYoutubeVideoParams{
+					TopicID:      topic.ID,
+					VideoID:      strings.TrimSpace(video.VideoID),
+					Provider:     importedVideoProvider(video.Provider),
+					Title:        util.GetSQLNullString(video.Title),
+					VideoDate:    sql.NullTime{Time: video.VideoDate, Valid: !video.VideoDate.IsZero()},
+					ChannelTitle: util.GetSQLNullString(video.ChannelTitle),
+					ChannelID:    util.GetSQLNullString(video.ChannelID),
+					ThumbnailUrl: util.GetSQLNullString(video.ThumbnailURL),
+					ExpiryAt:     sql.NullTime{Time: expiry, Valid: true},
+					IsManual:     video.Manual,
+					UpdatedBy:    util.GetNullUUID(userId),
+				})
+				if err != nil {
+					logger.Log.Errorf("failed to import video %s of topic %s, %s", video.VideoID, topic.ID, err.Error())
+					return http.StatusInternalServerError, err
+				}
+
+				if video.Pinned {
+					if _, err := qtx.PinYoutubeVideo(c, models.PinYoutubeVideoParams{
+						VideoID:   strings.TrimSpace(video.VideoID),
+						UpdatedBy: userId,
+						TopicID:   topic.ID,
+					}); err != nil {
+						logger.Log.Errorf("failed to pin video %s of topic %s, %s", video.VideoID, topic.ID, err.Error())
+						return http.StatusInternalServerError, err
+					}
+				}
+			}
+		}
+
+		return http.StatusCreated, nil
+	})
+}
+
+// ExportPlaylist renders the playlist in the given format and returns the
+// content along with a suggested file name and content type
+func ExportPlaylist(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	format string,
+) ([]byte, string, string, int, error) {
+	if format != FormatJSON && format != FormatMarkdown && format != FormatCSV {
+		return nil, constant.Blank, constant.Blank, http.StatusBadRequest, fmt.Errorf(
+			"unsupported format %q, use json, md or csv",
+			format,
+		)
+	}
+
+	playlist, err := playlistrepository.GetPlaylistExportQuery(c, playlistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, constant.Blank, constant.Blank, http.StatusNotFound, fmt.Errorf(
+				"playlist of id %s not found",
+				playlistID,
+			)
+		}
+		logger.Log.Errorf("failed to get playlist export of id %s, %s", playlistID, err.Error())
+		return nil, constant.Blank, constant.Blank, http.StatusInternalServerError, err
+	}
+
+	bundle := dto.PlaylistBundle{
+		Version: PlaylistBundleVersion,
+		Playlist: dto.PlaylistBundlePlaylist{
+			Name:         playlist.Name.String,
+			Description:  playlist.Description.String,
+			DomainName:   playlist.DomainName.String,
+			ThumbnailURL: playlist.ThumbnailUrl.String,
+			IsAIGen:      playlist.IsAIGen,
+			Topics:       playlist.Topics,
+		},
+	}
+
+	fileName := fmt.Sprintf("playlist-%s.%s", playlist.Code, format)
+
+	switch format {
+	case FormatMarkdown:
+		return renderMarkdownOutline(bundle), fileName, "text/markdown; charset=utf-8", http.StatusOK, nil
+	case FormatCSV:
+		content, err := renderCSVSheet(bundle)
+		if err != nil {
+			return nil, constant.Blank, constant.Blank, http.StatusInternalServerError, err
+		}
+		return content, fileName, "text/csv; charset=utf-8", http.StatusOK, nil
+	default:
+		content, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return nil, constant.Blank, constant.Blank, http.StatusInternalServerError, err
+		}
+		return content, fileName, "application/json; charset=utf-8", http.StatusOK, nil
+	}
+}
+
+func renderMarkdownOutline(bundle dto.PlaylistBundle) []byte {
+	var sb strings.Builder
+	playlist := bundle.Playlist
+
+	fmt.Fprintf(&sb, "# %s\n", playlist.Name)
+	fmt.Fprintf(&sb, "Domain: %s\n", playlist.DomainName)
+	if playlist.ThumbnailURL != constant.Blank {
+		fmt.Fprintf(&sb, "Thumbnail: %s\n", playlist.ThumbnailURL)
+	}
+	if playlist.IsAIGen {
+		sb.WriteString("AI Generated: true\n")
+	}
+	if playlist.Description != constant.Blank {
+		fmt.Fprintf(&sb, "\n%s\n", playlist.Description)
+	}
+
+	for i, topic := range playlist.Topics {
+		fmt.Fprintf(&sb, "\n## %d. %s\n", i+1, topic.Name)
+
+		for _, video := range topic.Videos {
+			fmt.Fprintf(
+				&sb,
+				"Video: %s | %s | %s | %s | %s",
+				markdownFieldEscaper.Replace(video.VideoID),
+				markdownFieldEscaper.Replace(video.Title),
+				markdownFieldEscaper.Replace(video.ChannelTitle),
+				markdownFieldEscaper.Replace(video.ThumbnailURL),
+				importedVideoProvider(video.Provider),
+			)
+
+			var flags []string
+			if video.Pinned {
+				flags = append(flags, videoFlagPinned)
+			}
+			if video.Manual {
+				flags = append(flags, videoFlagManual)
+			}
+			if len(flags) > 0 {
+				fmt.Fprintf(&sb, " | %s", strings.Join(flags, ", "))
+			}
+			sb.WriteString("\n")
+		}
+
+		if topic.StudyMaterial != nil {
+			fence := strings.Repeat("~", max(3, longestTildeRun(topic.StudyMaterial.Content)+1))
+			fmt.Fprintf(
+				&sb,
+				"### Material: %s\n%smarkdown\n%s\n%s\n",
+				topic.StudyMaterial.Title,
+				fence,
+				topic.StudyMaterial.Content,
+				fence,
+			)
+		}
+	}
+
+	return []byte(sb.String())
+}
+
+func renderCSVSheet(bundle dto.PlaylistBundle) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	playlist := bundle.Playlist
+
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	first := true
+	for _, topic := range playlist.Topics {
+		videos := topic.Videos
+		if len(videos) == 0 {
+			videos = []dto.PlaylistBundleVideo{{}}
+		}
+
+		for j, video := range videos {
+			record := make([]string, len(csvHeader))
+
+			if first {
+				record[0] = playlist.Name
+				record[1] = playlist.Description
+				record[2] = playlist.DomainName
+				record[3] = playlist.ThumbnailURL
+				record[4] = strconv.FormatBool(playlist.IsAIGen)
+				first = false
+			}
+
+			record[5] = topic.Name
+			record[6] = video.VideoID
+			record[7] = video.Title
+			record[8] = video.ChannelTitle
+			record[9] = video.ThumbnailURL
+			if video.VideoID != constant.Blank {
+				record[10] = importedVideoProvider(video.Provider)
+				record[11] = strconv.FormatBool(video.Pinned)
+				record[12] = strconv.FormatBool(video.Manual)
+			}
+
+			if j == 0 && topic.StudyMaterial != nil {
+				record[13] = topic.StudyMaterial.Title
+				record[14] = topic.StudyMaterial.Content
+			}
+
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
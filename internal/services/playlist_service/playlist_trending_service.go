@@ -0,0 +1,81 @@
+package playlistservice
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// RefreshPlaylistViewStats recomputes the 24h, 7d and all-time view counts and
+// the decayed trending score, rolls up events past the 7d window, then syncs
+// playlist.views with the legacy count plus the deduped total
+func RefreshPlaylistViewStats(ctx context.Context) error {
+	halfLife := config.GetConfig().TrendingHalfLife.Hours()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.RefreshPlaylistViewStats(ctx, halfLife); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to refresh playlist view stats, %s", err.Error())
+		return err
+	}
+
+	if _, err := qtx.RollUpPlaylistViews(ctx); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to roll up playlist views, %s", err.Error())
+		return err
+	}
+
+	if err := qtx.SyncPlaylistViewsFromStats(ctx); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to sync playlist views, %s", err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func GetTrendingPlaylists(
+	c *gin.Context,
+	limit int,
+) ([]dto.PlaylistPreviewDTO, int, error) {
+	playlists, err := db.Queries.GetTrendingPlaylists(c, int32(limit))
+	if err != nil {
+		logger.Log.Errorf("failed to get trending playlists, %s", err.Error())
+		return []dto.PlaylistPreviewDTO{}, http.StatusInternalServerError, err
+	}
+
+	serializedPlaylists := make([]dto.PlaylistPreviewDTO, len(playlists))
+	for i, playlist := range playlists {
+		serializedPlaylists[i] = dto.PlaylistPreviewDTO{
+			ID:            playlist.ID.String(),
+			Name:          playlist.Name.String,
+			Description:   playlist.Description.String,
+			InterestID:    playlist.InterestID.UUID.String(),
+			ThumbnailURL:  playlist.ThumbnailUrl.String,
+			Views:         int(playlist.Views.Int32),
+			AverageRating: playlist.AverageRating,
+			RatingCount:   int(playlist.RatingCount),
+			Views24h:      int(playlist.Views24h),
+			Views7d:       int(playlist.Views7d),
+			TrendingScore: playlist.TrendingScore,
+			Code:          playlist.Code,
+			CreatedAt:     playlist.CreatedAt.Time,
+			UpdatedAt:     playlist.UpdatedAt.Time,
+			UpdatedBy:     playlist.UpdatedBy.UUID.String(),
+			IsAIGen:       playlist.IsAiGen,
+			TopicsCount:   int(playlist.TopicsCount),
+		}
+	}
+
+	return serializedPlaylists, http.StatusAccepted, nil
+}
@@ -0,0 +1,314 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	topicrepository "github.com/easc01/mindo-server/internal/repository/topic_repository"
+	aiservice "github.com/easc01/mindo-server/internal/services/ai_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// bookmarkExcerptLength is how much of the notes a bookmark list shows
+const bookmarkExcerptLength = 200
+
+// studyMaterialDrafts keeps learners opening the same topic from generating
+// its notes twice
+var studyMaterialDrafts util.Coalescer[models.StudyMaterial]
+
+// GetStudyMaterialByTopicId returns the notes of a topic, topics without notes
+// get a generated draft on first read
+func GetStudyMaterialByTopicId(c *gin.Context, topicID uuid.UUID) (dto.StudyMaterialDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		return dto.StudyMaterialDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullUserContext)
+	}
+
+	var userID uuid.UUID
+	if user.AppUser != nil {
+		userID = user.AppUser.UserID
+	}
+
+	material, err := db.Queries.GetStudyMaterialByTopicId(c, models.GetStudyMaterialByTopicIdParams{
+		UserID:  userID,
+		TopicID: topicID,
+	})
+	if err == nil {
+		return serializeStudyMaterial(models.StudyMaterial{
+			ID:          material.ID,
+			TopicID:     material.TopicID,
+			Title:       material.Title,
+			Content:     material.Content,
+			ContentText: material.ContentText,
+			IsGenerated: material.IsGenerated,
+			UpdatedAt:   material.UpdatedAt,
+			CreatedAt:   material.CreatedAt,
+			UpdatedBy:   material.UpdatedBy,
+		}, material.IsBookmarked), http.StatusAccepted, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Errorf("failed to get study material of topic %s, %s", topicID, err.Error())
+		return dto.StudyMaterialDTO{}, http.StatusInternalServerError, err
+	}
+
+	draft, err, _ := studyMaterialDrafts.Do(topicID.String(), func() (models.StudyMaterial, error) {
+		return generateStudyMaterial(context.Background(), topicID)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return dto.StudyMaterialDTO{}, http.StatusNotFound, fmt.Errorf("topic of id %s not found", topicID)
+	}
+	if err != nil {
+		return dto.StudyMaterialDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeStudyMaterial(draft, false), http.StatusCreated, nil
+}
+
+// generateStudyMaterial drafts notes for a topic, when an admin saved notes
+// while the generator ran those are returned instead
+func generateStudyMaterial(ctx context.Context, topicID uuid.UUID) (models.StudyMaterial, error) {
+	topic, err := topicrepository.GetTopicByIDWithVideos(ctx, topicID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Errorf("failed to get topic %s for study material, %s", topicID, err.Error())
+		}
+		return models.StudyMaterial{}, err
+	}
+
+	generated, err := aiservice.GenerateStudyMaterial(dto.GenerateStudyMaterialParams{
+		TopicName:    topic.Name.String,
+		PlaylistName: topic.PlaylistName.String,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to generate study material of topic %s, %s", topicID, err.Error())
+		return models.StudyMaterial{}, err
+	}
+
+	title := generated.Title
+	if title == "" {
+		title = topic.Name.String
+	}
+
+	material, err := db.Queries.InsertGeneratedStudyMaterial(ctx, models.InsertGeneratedStudyMaterialParams{
+		TopicID:     topicID,
+		Title:       util.GetSQLNullString(title),
+		Content:     util.GetSQLNullString(generated.Content),
+		ContentText: util.GetSQLNullString(contentutil.PlainText(generated.Content)),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		saved, err := db.Queries.GetStudyMaterialByTopicId(ctx, models.GetStudyMaterialByTopicIdParams{
+			TopicID: topicID,
+		})
+		if err != nil {
+			logger.Log.Errorf("failed to get study material of topic %s, %s", topicID, err.Error())
+			return models.StudyMaterial{}, err
+		}
+		return models.StudyMaterial{
+			ID:          saved.ID,
+			TopicID:     saved.TopicID,
+			Title:       saved.Title,
+			Content:     saved.Content,
+			ContentText: saved.ContentText,
+			IsGenerated: saved.IsGenerated,
+			UpdatedAt:   saved.UpdatedAt,
+			CreatedAt:   saved.CreatedAt,
+			UpdatedBy:   saved.UpdatedBy,
+		}, nil
+	}
+	if err != nil {
+		logger.Log.Errorf("failed to save generated study material of topic %s, %s", topicID, err.Error())
+		return models.StudyMaterial{}, err
+	}
+
+	logger.Log.Infof("generated study material for topic %s", topicID)
+	return material, nil
+}
+
+// UpsertStudyMaterial saves admin authored notes, replacing any generated draft
+func UpsertStudyMaterial(
+	c *gin.Context,
+	topicID uuid.UUID,
+	req dto.UpsertStudyMaterialRequest,
+) (dto.StudyMaterialDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.StudyMaterialDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	if _, err := topicrepository.GetTopicByIDWithVideos(c, topicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.StudyMaterialDTO{}, http.StatusNotFound, fmt.Errorf("topic of id %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get topic %s, %s", topicID, err.Error())
+		return dto.StudyMaterialDTO{}, http.StatusInternalServerError, err
+	}
+
+	material, err := db.Queries.UpsertStudyMaterialByTopicId(c, models.UpsertStudyMaterialByTopicIdParams{
+		TopicID:     topicID,
+		Title:       util.GetSQLNullString(req.Title),
+		Content:     util.GetSQLNullString(req.Content),
+		ContentText: util.GetSQLNullString(contentutil.PlainText(req.Content)),
+		IsGenerated: false,
+		UpdatedBy:   util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to save study material of topic %s, %s", topicID, err.Error())
+		return dto.StudyMaterialDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeStudyMaterial(material, false), http.StatusAccepted, nil
+}
+
+// DeleteStudyMaterial removes the notes of a topic along with their bookmarks
+func DeleteStudyMaterial(c *gin.Context, topicID uuid.UUID) (int, error) {
+	material, statusCode, err := getStudyMaterialID(c, topicID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.DeleteStudyMaterialBookmarks(c, material); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to delete bookmarks of study material %s, %s", material, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if _, err := qtx.DeleteStudyMaterialById(c, material); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to delete study material %s, %s", material, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// BookmarkStudyMaterial saves the notes of a topic for the learner, saving
+// twice is a no-op
+func BookmarkStudyMaterial(c *gin.Context, topicID uuid.UUID) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	material, statusCode, err := getStudyMaterialID(c, topicID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	if err := db.Queries.BookmarkStudyMaterial(c, models.BookmarkStudyMaterialParams{
+		StudyMaterialID: material,
+		UserID:          user.AppUser.UserID,
+	}); err != nil {
+		logger.Log.Errorf("failed to bookmark study material %s, %s", material, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+func RemoveStudyMaterialBookmark(c *gin.Context, topicID uuid.UUID) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	material, statusCode, err := getStudyMaterialID(c, topicID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	removed, err := db.Queries.DeleteStudyMaterialBookmark(c, models.DeleteStudyMaterialBookmarkParams{
+		StudyMaterialID: material,
+		UserID:          user.AppUser.UserID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to remove bookmark of study material %s, %s", material, err.Error())
+		return http.StatusInternalServerError, err
+	}
+	if removed == 0 {
+		return http.StatusNotFound, fmt.Errorf("study material of topic %s is not bookmarked", topicID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+func GetBookmarkedStudyMaterials(c *gin.Context) ([]dto.StudyMaterialBookmarkDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return []dto.StudyMaterialBookmarkDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	rows, err := db.Queries.GetBookmarkedStudyMaterialsByUserId(c, user.AppUser.UserID)
+	if err != nil {
+		logger.Log.Errorf("failed to get bookmarked study materials, %s", err.Error())
+		return []dto.StudyMaterialBookmarkDTO{}, http.StatusInternalServerError, err
+	}
+
+	bookmarks := make([]dto.StudyMaterialBookmarkDTO, len(rows))
+	for i, row := range rows {
+		bookmarks[i] = dto.StudyMaterialBookmarkDTO{
+			ID:           row.ID,
+			TopicID:      row.TopicID,
+			TopicName:    row.TopicName.String,
+			PlaylistID:   row.PlaylistID,
+			PlaylistName: row.PlaylistName.String,
+			Title:        row.Title.String,
+			Excerpt:      contentutil.Excerpt(row.ContentText.String, bookmarkExcerptLength),
+			UpdatedAt:    row.UpdatedAt.Time,
+			BookmarkedAt: row.BookmarkedAt.Time,
+		}
+	}
+
+	return bookmarks, http.StatusAccepted, nil
+}
+
+func getStudyMaterialID(c *gin.Context, topicID uuid.UUID) (uuid.UUID, int, error) {
+	material, err := db.Queries.GetStudyMaterialByTopicId(c, models.GetStudyMaterialByTopicIdParams{
+		TopicID: topicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, http.StatusNotFound, fmt.Errorf("study material of topic %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get study material of topic %s, %s", topicID, err.Error())
+		return uuid.Nil, http.StatusInternalServerError, err
+	}
+
+	return material.ID, http.StatusOK, nil
+}
+
+func serializeStudyMaterial(material models.StudyMaterial, isBookmarked bool) dto.StudyMaterialDTO {
+	return dto.StudyMaterialDTO{
+		ID:           material.ID,
+		TopicID:      material.TopicID,
+		Title:        material.Title.String,
+		Content:      material.Content.String,
+		ContentHTML:  contentutil.HTML(material.Content.String),
+		IsGenerated:  material.IsGenerated,
+		IsBookmarked: isBookmarked,
+		UpdatedAt:    material.UpdatedAt.Time,
+		CreatedAt:    material.CreatedAt.Time,
+		UpdatedBy:    material.UpdatedBy.UUID,
+	}
+}
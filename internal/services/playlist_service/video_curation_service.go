@@ -0,0 +1,335 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	topicrepository "github.com/easc01/mindo-server/internal/repository/topic_repository"
+	youtubevideorepository "github.com/easc01/mindo-server/internal/repository/youtube_video_repository"
+	videoservice "github.com/easc01/mindo-server/internal/services/video_service"
+	youtubeservice "github.com/easc01/mindo-server/internal/services/youtube_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// videoBans holds every banned video and channel id, the table stays small
+// enough to load whole
+type videoBans struct {
+	videos   map[string]bool
+	channels map[string]bool
+}
+
+func loadVideoBans(ctx context.Context) (videoBans, error) {
+	rows, err := db.Queries.GetAllVideoBans(ctx)
+	if err != nil {
+		return videoBans{}, err
+	}
+
+	bans := videoBans{videos: map[string]bool{}, channels: map[string]bool{}}
+	for _, row := range rows {
+		switch row.Kind {
+		case constant.VideoBanKindVideo:
+			bans.videos[row.Value] = true
+		case constant.VideoBanKindChannel:
+			bans.channels[row.Value] = true
+		}
+	}
+	return bans, nil
+}
+
+func (b videoBans) isBanned(videoID string, channelID string) bool {
+	return b.videos[videoID] || (channelID != constant.Blank && b.channels[channelID])
+}
+
+// removeUnavailableVideos drops banned and report hidden videos read from the
+// database, watched ones outlive their ban for progress history
+func removeUnavailableVideos(ctx context.Context, videos []dto.VideoDataDTO) ([]dto.VideoDataDTO, error) {
+	bans, err := loadVideoBans(ctx)
+	if err != nil {
+		return videos, err
+	}
+
+	return slices.DeleteFunc(videos, func(video dto.VideoDataDTO) bool {
+		return video.IsHidden || bans.isBanned(video.VideoID, video.ChannelID)
+	}), nil
+}
+
+// PinTopicVideo makes a video of the topic its primary one, replacing any
+// earlier pin
+func PinTopicVideo(c *gin.Context, topicID uuid.UUID, videoID string) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	pinned, err := db.Queries.PinYoutubeVideo(c, models.PinYoutubeVideoParams{
+		VideoID:   videoID,
+		UpdatedBy: user.AdminUser.UserID,
+		TopicID:   topicID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to pin video %s of topic %s, %s", videoID, topicID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if pinned == 0 {
+		return http.StatusNotFound, fmt.Errorf("video %s not found in topic %s", videoID, topicID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+func UnpinTopicVideo(c *gin.Context, topicID uuid.UUID, videoID string) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	unpinned, err := db.Queries.UnpinYoutubeVideo(c, models.UnpinYoutubeVideoParams{
+		TopicID:   topicID,
+		VideoID:   videoID,
+		UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to unpin video %s of topic %s, %s", videoID, topicID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if unpinned == 0 {
+		return http.StatusNotFound, fmt.Errorf("no pinned video %s in topic %s", videoID, topicID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// AddTopicVideo attaches a youtube video to a topic by url, manual videos are
+// never removed by cache refreshes
+func AddTopicVideo(
+	c *gin.Context,
+	topicID uuid.UUID,
+	req dto.AddTopicVideoRequest,
+) (dto.VideoDataDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.VideoDataDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	videoID, err := youtubeservice.ParseVideoID(req.URL)
+	if err != nil {
+		return dto.VideoDataDTO{}, http.StatusBadRequest, err
+	}
+
+	if _, err := topicrepository.GetTopicByIDWithVideos(c, topicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.VideoDataDTO{}, http.StatusNotFound, fmt.Errorf("topic of id %s not found", topicID)
+		}
+		logger.Log.Errorf("failed to get topic of id %s, %s", topicID, err.Error())
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+
+	video, err := youtubeservice.GetVideoById(c, videoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, youtubeservice.ErrVideoNotFound):
+			return dto.VideoDataDTO{}, http.StatusNotFound, err
+		case errors.Is(err, youtubeservice.ErrQuotaExhausted):
+			return dto.VideoDataDTO{}, http.StatusServiceUnavailable, err
+		}
+		logger.Log.Errorf("failed to get youtube video %s, %s", videoID, err.Error())
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+
+	bans, err := loadVideoBans(c)
+	if err != nil {
+		logger.Log.Errorf("failed to get video bans, %s", err.Error())
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+	if bans.isBanned(video.VideoID, video.ChannelID) {
+		return dto.VideoDataDTO{}, http.StatusConflict, fmt.Errorf("video %s or its channel is banned", videoID)
+	}
+
+	video = videoservice.RankVideos([]dto.VideoMiniDTO{video})[0]
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	saved, err := qtx.UpsertYoutubeVideo(c, models.UpsertYoutubeVideoParams{
+		TopicID:         topicID,
+		VideoID:         video.VideoID,
+		Provider:        video.Provider,
+		Title:           util.GetSQLNullString(video.Title),
+		VideoDate:       sql.NullTime{Time: video.VideoDate, Valid: !video.VideoDate.IsZero()},
+		ChannelTitle:    util.GetSQLNullString(video.ChannelTitle),
+		ChannelID:       util.GetSQLNullString(video.ChannelID),
+		ThumbnailUrl:    util.GetSQLNullString(video.ThumbnailURL),
+		DurationSeconds: util.GetSQLNullInt32(video.DurationSeconds),
+		ViewCount:       util.GetSQLNullInt64(video.ViewCount),
+		LikeCount:       util.GetSQLNullInt64(video.LikeCount),
+		HasCaptions:     video.HasCaptions,
+		Definition:      util.GetSQLNullString(video.Definition),
+		RankScore:       video.RankScore,
+		ExpiryAt:        sql.NullTime{Time: time.Now().Add(youtubevideorepository.VideoCacheTTL), Valid: true},
+		IsManual:        true,
+		UpdatedBy:       util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to add video %s to topic %s, %s", videoID, topicID, err.Error())
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+
+	if req.Pin {
+		if _, err := qtx.PinYoutubeVideo(c, models.PinYoutubeVideoParams{
+			VideoID:   saved.VideoID,
+			UpdatedBy: user.AdminUser.UserID,
+			TopicID:   topicID,
+		}); err != nil {
+			tx.Rollback()
+			logger.Log.Errorf("failed to pin video %s of topic %s, %s", videoID, topicID, err.Error())
+			return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+		}
+		saved.IsPinned = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.VideoDataDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeYoutubeVideo(saved), http.StatusCreated, nil
+}
+
+// BanVideo bans a video or a channel everywhere and removes its videos from
+// every topic
+func BanVideo(c *gin.Context, req dto.CreateVideoBanRequest) (dto.VideoBanDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.VideoBanDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	value := req.Value
+	if req.Kind == constant.VideoBanKindVideo {
+		videoID, err := youtubeservice.ParseVideoID(req.Value)
+		if err != nil {
+			return dto.VideoBanDTO{}, http.StatusBadRequest, err
+		}
+		value = videoID
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.VideoBanDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	ban, err := qtx.CreateVideoBan(c, models.CreateVideoBanParams{
+		Kind:      req.Kind,
+		Value:     value,
+		Reason:    util.GetSQLNullString(req.Reason),
+		UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to ban %s %s, %s", req.Kind, value, err.Error())
+		return dto.VideoBanDTO{}, http.StatusInternalServerError, err
+	}
+
+	removed, err := qtx.DeleteBannedYoutubeVideos(c, models.DeleteBannedYoutubeVideosParams{
+		Kind:  ban.Kind,
+		Value: ban.Value,
+	})
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to remove videos of banned %s %s, %s", ban.Kind, ban.Value, err.Error())
+		return dto.VideoBanDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.VideoBanDTO{}, http.StatusInternalServerError, err
+	}
+
+	logger.Log.Infof("banned %s %s, removed %d videos", ban.Kind, ban.Value, removed)
+	return serializeVideoBan(ban), http.StatusCreated, nil
+}
+
+// UnbanVideo lifts a ban, removed videos come back with the next search
+func UnbanVideo(c *gin.Context, banID uuid.UUID) (int, error) {
+	deleted, err := db.Queries.DeleteVideoBanById(c, banID)
+	if err != nil {
+		logger.Log.Errorf("failed to delete video ban %s, %s", banID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf("video ban of id %s not found", banID)
+	}
+
+	return http.StatusAccepted, nil
+}
+
+func GetVideoBans(c *gin.Context) ([]dto.VideoBanDTO, int, error) {
+	rows, err := db.Queries.GetAllVideoBans(c)
+	if err != nil {
+		logger.Log.Errorf("failed to get video bans, %s", err.Error())
+		return []dto.VideoBanDTO{}, http.StatusInternalServerError, err
+	}
+
+	bans := make([]dto.VideoBanDTO, len(rows))
+	for i, row := range rows {
+		bans[i] = serializeVideoBan(row)
+	}
+	return bans, http.StatusAccepted, nil
+}
+
+func serializeVideoBan(ban models.VideoBan) dto.VideoBanDTO {
+	return dto.VideoBanDTO{
+		ID:        ban.ID,
+		Kind:      ban.Kind,
+		Value:     ban.Value,
+		Reason:    ban.Reason.String,
+		CreatedAt: ban.CreatedAt.Time,
+		UpdatedBy: ban.UpdatedBy.UUID.String(),
+	}
+}
+
+func serializeYoutubeVideo(video models.YoutubeVideo) dto.VideoDataDTO {
+	return dto.VideoDataDTO{
+		ID:              video.ID.String(),
+		TopicID:         video.TopicID.String(),
+		VideoID:         video.VideoID,
+		Provider:        video.Provider,
+		Title:           video.Title.String,
+		VideoDate:       video.VideoDate.Time,
+		ChannelTitle:    video.ChannelTitle.String,
+		ChannelID:       video.ChannelID.String,
+		ThumbnailURL:    video.ThumbnailUrl.String,
+		DurationSeconds: int(video.DurationSeconds.Int32),
+		ViewCount:       video.ViewCount.Int64,
+		LikeCount:       video.LikeCount.Int64,
+		HasCaptions:     video.HasCaptions,
+		Definition:      video.Definition.String,
+		RankScore:       video.RankScore,
+		ExpiryAt:        video.ExpiryAt.Time,
+		IsPinned:        video.IsPinned,
+		IsManual:        video.IsManual,
+		IsHidden:        video.IsHidden,
+		CreatedAt:       video.CreatedAt.Time,
+		UpdatedAt:       video.UpdatedAt.Time,
+		UpdatedBy:       video.UpdatedBy.UUID.String(),
+	}
+}
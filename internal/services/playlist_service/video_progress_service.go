@@ -0,0 +1,307 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// progressIdleTTL is how long a flushed heartbeat entry stays cached after the
+// learner's last heartbeat
+const progressIdleTTL = 10 * time.Minute
+
+type progressKey struct {
+	userID  uuid.UUID
+	topicID uuid.UUID
+	videoID string
+}
+
+// videoProgress is the latest heartbeat of a learner on a video, kept in
+// memory and written by FlushVideoProgress
+type videoProgress struct {
+	youtubeVideoID  uuid.UUID
+	durationSeconds int
+	positionSeconds int
+	watchSeconds    int
+	isWatched       bool
+	dirty           bool
+	touchedAt       time.Time
+}
+
+// progressBuffer debounces playback heartbeats, only the newest one per video
+// reaches the database on each flush
+var progressBuffer = struct {
+	sync.Mutex
+	entries map[progressKey]*videoProgress
+}{entries: map[progressKey]*videoProgress{}}
+
+// WatchedHook runs after a learner's video is saved as watched, the topic and
+// with it the playlist may be completed now
+type WatchedHook func(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) error
+
+var (
+	watchedHooksMu sync.RWMutex
+	watchedHooks   []WatchedHook
+)
+
+// RegisterWatchedHook appends a hook run after every newly watched video
+func RegisterWatchedHook(hook WatchedHook) {
+	watchedHooksMu.Lock()
+	defer watchedHooksMu.Unlock()
+	watchedHooks = append(watchedHooks, hook)
+}
+
+// RecordVideoProgress buffers a playback heartbeat, it is written right away
+// only when it marks the video watched
+func RecordVideoProgress(
+	c *gin.Context,
+	topicID uuid.UUID,
+	videoID string,
+	req dto.VideoProgressRequest,
+) (dto.VideoProgressDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.VideoProgressDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	key := progressKey{userID: user.AppUser.UserID, topicID: topicID, videoID: videoID}
+
+	progressBuffer.Lock()
+	entry, cached := progressBuffer.entries[key]
+	progressBuffer.Unlock()
+
+	if !cached {
+		loaded, statusCode, err := loadVideoProgress(c, key)
+		if err != nil {
+			return dto.VideoProgressDTO{}, statusCode, err
+		}
+
+		progressBuffer.Lock()
+		// a concurrent heartbeat may have loaded it first
+		if entry, cached = progressBuffer.entries[key]; !cached {
+			entry = loaded
+		}
+		progressBuffer.Unlock()
+	}
+
+	watchedPercent := config.GetConfig().VideoWatchedPercent
+
+	progressBuffer.Lock()
+	// put back in case a flush evicted it since the lookup
+	progressBuffer.entries[key] = entry
+	entry.positionSeconds = req.PositionSeconds
+	entry.watchSeconds = max(entry.watchSeconds, req.WatchSeconds)
+	if entry.durationSeconds == 0 {
+		entry.durationSeconds = req.DurationSeconds
+	}
+
+	newlyWatched := !entry.isWatched &&
+		entry.durationSeconds > 0 &&
+		entry.watchSeconds*100 >= entry.durationSeconds*watchedPercent
+	if newlyWatched {
+		entry.isWatched = true
+	}
+
+	entry.dirty = !newlyWatched
+	entry.touchedAt = time.Now()
+	snapshot := *entry
+	progressBuffer.Unlock()
+
+	// watching completes topics, progress and certificates must see it now
+	if newlyWatched {
+		if err := writeVideoProgress(c, key.userID, snapshot); err != nil {
+			progressBuffer.Lock()
+			entry.dirty = true
+			progressBuffer.Unlock()
+
+			logger.Log.Errorf("failed to save watched video %s, %s", videoID, err.Error())
+			return dto.VideoProgressDTO{}, http.StatusInternalServerError, err
+		}
+
+		// the progress is saved, a failing hook must not report it lost
+		watchedHooksMu.RLock()
+		for _, hook := range watchedHooks {
+			if err := hook(c, key.userID, topicID); err != nil {
+				logger.Log.Errorf("failed to run watched hook of topic %s, %s", topicID, err.Error())
+			}
+		}
+		watchedHooksMu.RUnlock()
+	}
+
+	return dto.VideoProgressDTO{
+		VideoID:         videoID,
+		PositionSeconds: snapshot.positionSeconds,
+		WatchSeconds:    snapshot.watchSeconds,
+		IsWatched:       snapshot.isWatched,
+	}, http.StatusAccepted, nil
+}
+
+// loadVideoProgress resolves the topic video and seeds the entry with the
+// stored progress, so watch time never goes backwards after a restart
+func loadVideoProgress(c *gin.Context, key progressKey) (*videoProgress, int, error) {
+	video, err := db.Queries.GetYoutubeVideoByTopicId(c, models.GetYoutubeVideoByTopicIdParams{
+		TopicID: key.topicID,
+		VideoID: key.videoID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, http.StatusNotFound, fmt.Errorf("video %s not found in topic %s", key.videoID, key.topicID)
+		}
+		logger.Log.Errorf("failed to get video %s of topic %s, %s", key.videoID, key.topicID, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+
+	entry := &videoProgress{
+		youtubeVideoID:  video.ID,
+		durationSeconds: int(video.DurationSeconds.Int32),
+	}
+
+	stored, err := db.Queries.GetVideoProgressByUserId(c, models.GetVideoProgressByUserIdParams{
+		UserID:          key.userID,
+		YoutubeVideoIds: []uuid.UUID{video.ID},
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get progress of video %s, %s", key.videoID, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+
+	for _, row := range stored {
+		entry.positionSeconds = int(row.PositionSeconds)
+		entry.watchSeconds = int(row.WatchSeconds)
+		entry.isWatched = row.IsWatched
+	}
+
+	return entry, http.StatusOK, nil
+}
+
+func writeVideoProgress(ctx context.Context, userID uuid.UUID, progress videoProgress) error {
+	return db.Queries.UpsertVideoProgress(ctx, models.UpsertVideoProgressParams{
+		UserID:          userID,
+		YoutubeVideoID:  progress.youtubeVideoID,
+		PositionSeconds: int32(progress.positionSeconds),
+		WatchSeconds:    int32(progress.watchSeconds),
+		IsWatched:       progress.isWatched,
+	})
+}
+
+// FlushVideoProgress writes every buffered heartbeat and forgets learners who
+// stopped playing
+func FlushVideoProgress(ctx context.Context) error {
+	type pendingWrite struct {
+		key      progressKey
+		progress videoProgress
+	}
+
+	var pending []pendingWrite
+	now := time.Now()
+
+	progressBuffer.Lock()
+	for key, entry := range progressBuffer.entries {
+		if entry.dirty {
+			pending = append(pending, pendingWrite{key: key, progress: *entry})
+			entry.dirty = false
+		} else if now.Sub(entry.touchedAt) > progressIdleTTL {
+			delete(progressBuffer.entries, key)
+		}
+	}
+	progressBuffer.Unlock()
+
+	failed := 0
+	for _, write := range pending {
+		if err := writeVideoProgress(ctx, write.key.userID, write.progress); err != nil {
+			failed++
+			logger.Log.Errorf("failed to save progress of video %s, %s", write.key.videoID, err.Error())
+
+			// retried on the next flush unless a newer heartbeat replaced the entry
+			progressBuffer.Lock()
+			if entry, ok := progressBuffer.entries[write.key]; ok {
+				entry.dirty = true
+			}
+			progressBuffer.Unlock()
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to save %d of %d video progress entries", failed, len(pending))
+	}
+	return nil
+}
+
+// withViewerProgress returns a copy of videos carrying the playback of the
+// requesting learner, the copy keeps coalesced fetch results untouched
+func withViewerProgress(c *gin.Context, videos []dto.VideoDataDTO) []dto.VideoDataDTO {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return videos
+	}
+
+	videos = slices.Clone(videos)
+	if err := attachVideoProgress(c, user.AppUser.UserID, videos); err != nil {
+		logger.Log.Errorf("failed to get video progress of user %s, %s", user.AppUser.UserID, err.Error())
+	}
+	return videos
+}
+
+// attachVideoProgress fills the requesting learner's playback into videos,
+// buffered heartbeats win over what the database holds
+func attachVideoProgress(ctx context.Context, userID uuid.UUID, videos []dto.VideoDataDTO) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(videos))
+	for _, video := range videos {
+		if id, err := uuid.Parse(video.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	stored, err := db.Queries.GetVideoProgressByUserId(ctx, models.GetVideoProgressByUserIdParams{
+		UserID:          userID,
+		YoutubeVideoIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	byVideo := make(map[string]models.GetVideoProgressByUserIdRow, len(stored))
+	for _, row := range stored {
+		byVideo[row.YoutubeVideoID.String()] = row
+	}
+
+	progressBuffer.Lock()
+	defer progressBuffer.Unlock()
+
+	for i := range videos {
+		if row, ok := byVideo[videos[i].ID]; ok {
+			videos[i].ResumePositionSeconds = int(row.PositionSeconds)
+			videos[i].WatchSeconds = int(row.WatchSeconds)
+			videos[i].IsWatched = row.IsWatched
+		}
+
+		topicID, _ := uuid.Parse(videos[i].TopicID)
+		key := progressKey{userID: userID, topicID: topicID, videoID: videos[i].VideoID}
+		if entry, ok := progressBuffer.entries[key]; ok {
+			videos[i].ResumePositionSeconds = entry.positionSeconds
+			videos[i].WatchSeconds = entry.watchSeconds
+			videos[i].IsWatched = entry.isWatched
+		}
+	}
+
+	return nil
+}
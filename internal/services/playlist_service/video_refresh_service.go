@@ -0,0 +1,162 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/models"
+	youtubevideorepository "github.com/easc01/mindo-server/internal/repository/youtube_video_repository"
+	videoservice "github.com/easc01/mindo-server/internal/services/video_service"
+	youtubeservice "github.com/easc01/mindo-server/internal/services/youtube_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/google/uuid"
+)
+
+const (
+	videoSearchResults = 10
+
+	// most topics one refresh run re-searches, the quota ledger caps it further
+	refreshBatchSize = 50
+)
+
+func videoSearchQuery(topicName string, playlistName string) string {
+	return fmt.Sprintf("%s in %s", topicName, playlistName)
+}
+
+// searchTopicVideos searches the configured provider for a topic and returns
+// the results without banned videos, ranked best first
+func searchTopicVideos(ctx context.Context, topicName string, playlistName string) ([]dto.VideoMiniDTO, error) {
+	videos, err := videoservice.GetProvider().SearchVideos(
+		ctx,
+		videoSearchQuery(topicName, playlistName),
+		videoSearchResults,
+	)
+	if err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+
+	bans, err := loadVideoBans(ctx)
+	if err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+	videos = slices.DeleteFunc(videos, func(video dto.VideoMiniDTO) bool {
+		return bans.isBanned(video.VideoID, video.ChannelID)
+	})
+
+	return videoservice.RankVideos(videos), nil
+}
+
+// RefreshExpiredVideos re-searches topics whose cached videos expired, it stops
+// early once the quota outside the learner reserve is spent
+func RefreshExpiredVideos(ctx context.Context) error {
+	ctx = youtubeservice.WithBackgroundPriority(ctx)
+
+	topics, err := db.Queries.GetTopicsWithExpiredVideos(ctx, refreshBatchSize)
+	if err != nil {
+		logger.Log.Errorf("failed to get topics with expired videos, %s", err.Error())
+		return err
+	}
+
+	refreshed := 0
+	for _, topic := range topics {
+		err := refreshTopicVideos(ctx, topic.ID, topic.Name.String, topic.PlaylistName.String)
+		if err != nil {
+			if errors.Is(err, youtubeservice.ErrQuotaExhausted) {
+				logger.Log.Infof("video refresh paused, background youtube quota used up")
+				break
+			}
+			logger.Log.Errorf("failed to refresh videos of topic %s, %s", topic.ID, err.Error())
+			continue
+		}
+		refreshed++
+	}
+
+	logger.Log.Infof("refreshed videos of %d of %d expired topics", refreshed, len(topics))
+	return nil
+}
+
+// refreshTopicVideos replaces the searched videos of a topic with a fresh search
+// in one transaction, videos found again are upserted instead of duplicated
+func refreshTopicVideos(
+	ctx context.Context,
+	topicID uuid.UUID,
+	topicName string,
+	playlistName string,
+) error {
+	videos, err := searchTopicVideos(ctx, topicName, playlistName)
+	if err != nil {
+		return err
+	}
+
+	expiry := sql.NullTime{Time: time.Now().Add(youtubevideorepository.VideoCacheTTL), Valid: true}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	keepVideoIDs := make([]string, 0, len(videos))
+	for _, video := range videos {
+		// a search can return the same video twice, the first save covers it
+		if video.VideoID == constant.Blank || slices.Contains(keepVideoIDs, video.VideoID) {
+			continue
+		}
+		keepVideoIDs = append(keepVideoIDs, video.VideoID)
+
+		_, err := qtx.UpsertYoutubeVideo(ctx, models.UpsertYoutubeVideoParams{
+			TopicID:      topicID,
+			VideoID:      video.VideoID,
+			Provider:     video.Provider,
+			Title:        util.GetSQLNullString(video.Title),
+			VideoDate:    sql.NullTime{Time: video.VideoDate, Valid: !video.VideoDate.IsZero()},
+			ChannelTitle: util.GetSQLNullString(video.ChannelTitle),
+			ChannelID:    util.GetSQLNullString(video.ChannelID),
+			ThumbnailUrl: util.GetSQLNullString(video.ThumbnailURL),
+
+			DurationSeconds: util.GetSQLNullInt32(video.DurationSeconds),
+			ViewCount:       util.GetSQLNullInt64(video.ViewCount),
+			LikeCount:       util.GetSQLNullInt64(video.LikeCount),
+			HasCaptions:     video.HasCaptions,
+			Definition:      util.GetSQLNullString(video.Definition),
+			RankScore:       video.RankScore,
+			ExpiryAt:        expiry,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// An empty search keeps the current videos rather than wiping the topic
+	if len(keepVideoIDs) > 0 {
+		removed, err := qtx.DeleteStaleYoutubeVideosByTopicId(ctx, models.DeleteStaleYoutubeVideosByTopicIdParams{
+			TopicID:      topicID,
+			KeepVideoIds: keepVideoIDs,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		logger.Log.Debugf("removed %d stale videos of topic %s", removed, topicID)
+	}
+
+	// Videos kept for watch history must not keep the topic in the expired queue
+	if err := qtx.ExtendYoutubeVideoExpiryByTopicId(ctx, models.ExtendYoutubeVideoExpiryByTopicIdParams{
+		TopicID:  topicID,
+		ExpiryAt: expiry,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
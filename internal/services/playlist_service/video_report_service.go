@@ -0,0 +1,209 @@
+package playlistservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	topicrepository "github.com/easc01/mindo-server/internal/repository/topic_repository"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// topicVideoReplacements keeps reports crossing the threshold together from
+// searching the same topic twice
+var topicVideoReplacements util.Coalescer[struct{}]
+
+// ReportVideo records a learner's report of a topic video, once the open
+// reports reach the threshold the video is hidden and a replacement is searched
+func ReportVideo(
+	c *gin.Context,
+	topicID uuid.UUID,
+	videoID string,
+	req dto.ReportVideoRequest,
+) (dto.VideoReportResultDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.VideoReportResultDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	video, err := db.Queries.GetYoutubeVideoByTopicId(c, models.GetYoutubeVideoByTopicIdParams{
+		TopicID: topicID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.VideoReportResultDTO{}, http.StatusNotFound, fmt.Errorf("video %s not found in topic %s", videoID, topicID)
+		}
+		logger.Log.Errorf("failed to get video %s of topic %s, %s", videoID, topicID, err.Error())
+		return dto.VideoReportResultDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := db.Queries.UpsertVideoReport(c, models.UpsertVideoReportParams{
+		TopicID: topicID,
+		VideoID: videoID,
+		UserID:  user.AppUser.UserID,
+		Reason:  req.Reason,
+		Note:    util.GetSQLNullString(req.Note),
+	}); err != nil {
+		logger.Log.Errorf("failed to report video %s of topic %s, %s", videoID, topicID, err.Error())
+		return dto.VideoReportResultDTO{}, http.StatusInternalServerError, err
+	}
+
+	reports, err := db.Queries.CountOpenVideoReports(c, models.CountOpenVideoReportsParams{
+		TopicID: topicID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to count reports of video %s, %s", videoID, err.Error())
+		return dto.VideoReportResultDTO{}, http.StatusInternalServerError, err
+	}
+
+	hidden := video.IsHidden
+	if !hidden && reports >= int64(config.GetConfig().VideoReportThreshold) {
+		if _, err := db.Queries.SetYoutubeVideoHidden(c, models.SetYoutubeVideoHiddenParams{
+			TopicID:  topicID,
+			VideoID:  videoID,
+			IsHidden: true,
+		}); err != nil {
+			logger.Log.Errorf("failed to hide reported video %s, %s", videoID, err.Error())
+			return dto.VideoReportResultDTO{}, http.StatusInternalServerError, err
+		}
+		hidden = true
+
+		logger.Log.Infof("hid video %s of topic %s after %d reports", videoID, topicID, reports)
+		go replaceHiddenVideo(topicID)
+	}
+
+	return dto.VideoReportResultDTO{
+		VideoID:      videoID,
+		ReportsCount: int(reports),
+		IsHidden:     hidden,
+	}, http.StatusCreated, nil
+}
+
+// replaceHiddenVideo re-searches a topic after one of its videos got hidden,
+// it runs after the report request has finished
+func replaceHiddenVideo(topicID uuid.UUID) {
+	topicVideoReplacements.Do(topicID.String(), func() (struct{}, error) {
+		ctx := context.Background()
+
+		topic, err := topicrepository.GetTopicByIDWithVideos(ctx, topicID)
+		if err != nil {
+			logger.Log.Errorf("failed to get topic %s for video replacement, %s", topicID, err.Error())
+			return struct{}{}, err
+		}
+
+		err = refreshTopicVideos(ctx, topicID, topic.Name.String, topic.PlaylistName.String)
+		if err != nil {
+			logger.Log.Errorf("failed to replace hidden video of topic %s, %s", topicID, err.Error())
+		}
+		return struct{}{}, err
+	})
+}
+
+// GetVideoReportQueue returns reported videos awaiting an admin, most reported first
+func GetVideoReportQueue(c *gin.Context, limit int, offset int) ([]dto.VideoReportQueueItemDTO, int, error) {
+	rows, err := db.Queries.GetOpenVideoReportQueue(c, models.GetOpenVideoReportQueueParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get video report queue, %s", err.Error())
+		return []dto.VideoReportQueueItemDTO{}, http.StatusInternalServerError, err
+	}
+
+	queue := make([]dto.VideoReportQueueItemDTO, len(rows))
+	for i, row := range rows {
+		reasons := make(map[string]int)
+		for _, reason := range row.Reasons {
+			reasons[reason]++
+		}
+
+		queue[i] = dto.VideoReportQueueItemDTO{
+			TopicID:        row.TopicID,
+			VideoID:        row.VideoID,
+			TopicName:      row.TopicName.String,
+			PlaylistName:   row.PlaylistName.String,
+			Title:          row.Title.String,
+			IsHidden:       row.IsHidden,
+			ReportsCount:   int(row.ReportsCount),
+			Reasons:        reasons,
+			Notes:          row.Notes,
+			LastReportedAt: row.LastReportedAt,
+		}
+	}
+
+	return queue, http.StatusAccepted, nil
+}
+
+// ResolveVideoReports closes the open reports of a topic video, dismissing
+// them brings the video back, hide keeps it hidden and ban bans it everywhere
+func ResolveVideoReports(
+	c *gin.Context,
+	topicID uuid.UUID,
+	videoID string,
+	req dto.ResolveVideoReportRequest,
+) (int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	reports, err := db.Queries.CountOpenVideoReports(c, models.CountOpenVideoReportsParams{
+		TopicID: topicID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to count reports of video %s, %s", videoID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	if reports == 0 {
+		return http.StatusNotFound, fmt.Errorf("no open reports for video %s in topic %s", videoID, topicID)
+	}
+
+	if req.Action == constant.VideoReportBan {
+		if _, statusCode, err := BanVideo(c, dto.CreateVideoBanRequest{
+			Kind:   constant.VideoBanKindVideo,
+			Value:  videoID,
+			Reason: "banned from learner reports",
+		}); err != nil {
+			return statusCode, err
+		}
+	} else {
+		if _, err := db.Queries.SetYoutubeVideoHidden(c, models.SetYoutubeVideoHiddenParams{
+			TopicID:  topicID,
+			VideoID:  videoID,
+			IsHidden: req.Action == constant.VideoReportHide,
+		}); err != nil {
+			logger.Log.Errorf("failed to update reported video %s, %s", videoID, err.Error())
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	resolved, err := db.Queries.ResolveVideoReports(c, models.ResolveVideoReportsParams{
+		TopicID:    topicID,
+		VideoID:    videoID,
+		Resolution: util.GetSQLNullString(req.Action),
+		UpdatedBy:  util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to resolve reports of video %s, %s", videoID, err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	logger.Log.Infof("resolved %d reports of video %s with %s", resolved, videoID, req.Action)
+	return http.StatusAccepted, nil
+}
@@ -0,0 +1,319 @@
+package quizservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	LeaderboardPeriodWeek    = "week"
+	LeaderboardPeriodAllTime = "all_time"
+)
+
+// RefreshPlaylistLeaderboards recomputes the weekly and all-time boards of
+// every playlist from the first attempts, rows no longer earned are dropped
+func RefreshPlaylistLeaderboards(ctx context.Context) error {
+	// timestamps keep microseconds, a finer refresh time would let the stale
+	// row cleanup drop the rows just written
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.RefreshPlaylistLeaderboardStats(ctx, models.RefreshPlaylistLeaderboardStatsParams{
+		RefreshedAt: now,
+		WeekStart:   weekStart(now),
+	}); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to refresh playlist leaderboards, %s", err.Error())
+		return err
+	}
+
+	if _, err := qtx.DeleteStalePlaylistLeaderboardStats(ctx, now); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to drop stale playlist leaderboard rows, %s", err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetQuizLeaderboard ranks the first attempts on a quiz by score, retakes
+// never move a learner
+func GetQuizLeaderboard(c *gin.Context, quizID uuid.UUID, limit int, offset int) (dto.LeaderboardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.LeaderboardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	if _, err := db.Queries.GetQuizById(c, quizID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LeaderboardDTO{}, http.StatusNotFound, fmt.Errorf("quiz of id %s not found", quizID)
+		}
+		logger.Log.Errorf("failed to get quiz %s, %s", quizID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	rows, err := db.Queries.GetQuizLeaderboard(c, models.GetQuizLeaderboardParams{
+		QuizID:    quizID,
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get leaderboard of quiz %s, %s", quizID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	leaderboard := dto.LeaderboardDTO{Entries: make([]dto.LeaderboardEntryDTO, len(rows))}
+	for i, row := range rows {
+		submittedAt := row.SubmittedAt
+		leaderboard.Entries[i] = dto.LeaderboardEntryDTO{
+			Rank:              int(row.Rank),
+			UserID:            row.UserID,
+			Username:          row.Username.String,
+			Name:              row.Name.String,
+			ProfilePictureUrl: row.ProfilePictureUrl.String,
+			Color:             row.Color,
+			Score:             row.Score,
+			CompletionSeconds: int64(row.CompletionSeconds),
+			SubmittedAt:       &submittedAt,
+		}
+	}
+
+	position, err := db.Queries.GetQuizLeaderboardPosition(c, models.GetQuizLeaderboardPositionParams{
+		QuizID: quizID,
+		UserID: user.AppUser.UserID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Errorf("failed to get leaderboard position on quiz %s, %s", quizID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+	if err == nil {
+		me := ownLeaderboardEntry(user.AppUser, position.Rank, position.Score, 0, int64(position.CompletionSeconds))
+		me.SubmittedAt = &position.SubmittedAt
+		leaderboard.Me = &me
+	}
+
+	return leaderboard, http.StatusAccepted, nil
+}
+
+// GetPlaylistLeaderboard ranks learners by the sum of their first attempt
+// scores across the quizzes and exam of a playlist, boards are refreshed
+// periodically so a new attempt shows up after the next refresh
+func GetPlaylistLeaderboard(
+	c *gin.Context,
+	playlistID uuid.UUID,
+	period string,
+	limit int,
+	offset int,
+) (dto.LeaderboardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.LeaderboardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	if _, err := periodStart(period, time.Now().UTC()); err != nil {
+		return dto.LeaderboardDTO{}, http.StatusBadRequest, err
+	}
+
+	if _, err := db.Queries.GetPlaylistById(c, playlistID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LeaderboardDTO{}, http.StatusNotFound, fmt.Errorf("playlist of id %s not found", playlistID)
+		}
+		logger.Log.Errorf("failed to get playlist %s, %s", playlistID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	rows, err := db.Queries.GetPlaylistLeaderboard(c, models.GetPlaylistLeaderboardParams{
+		PlaylistID: playlistID,
+		Period:     period,
+		RowLimit:   int32(limit),
+		RowOffset:  int32(offset),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get leaderboard of playlist %s, %s", playlistID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	leaderboard := dto.LeaderboardDTO{Period: period, Entries: make([]dto.LeaderboardEntryDTO, len(rows))}
+	for i, row := range rows {
+		leaderboard.Entries[i] = dto.LeaderboardEntryDTO{
+			Rank:              int(row.Rank),
+			UserID:            row.UserID,
+			Username:          row.Username.String,
+			Name:              row.Name.String,
+			ProfilePictureUrl: row.ProfilePictureUrl.String,
+			Color:             row.Color,
+			Score:             row.TotalScore,
+			QuizzesCompleted:  int(row.QuizzesCompleted),
+			CompletionSeconds: row.CompletionSeconds,
+		}
+	}
+
+	position, err := db.Queries.GetPlaylistLeaderboardPosition(c, models.GetPlaylistLeaderboardPositionParams{
+		PlaylistID: playlistID,
+		Period:     period,
+		UserID:     user.AppUser.UserID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Errorf("failed to get leaderboard position on playlist %s, %s", playlistID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+	if err == nil {
+		me := ownLeaderboardEntry(
+			user.AppUser,
+			int64(position.Rank),
+			position.TotalScore,
+			position.QuizzesCompleted,
+			position.CompletionSeconds,
+		)
+		leaderboard.Me = &me
+	}
+
+	return leaderboard, http.StatusAccepted, nil
+}
+
+// GetCommunityLeaderboard ranks the members of a community by the sum of their
+// first attempt scores on the quizzes and exams of playlists
+func GetCommunityLeaderboard(
+	c *gin.Context,
+	communityID uuid.UUID,
+	period string,
+	limit int,
+	offset int,
+) (dto.LeaderboardDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.LeaderboardDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	since, err := periodStart(period, time.Now().UTC())
+	if err != nil {
+		return dto.LeaderboardDTO{}, http.StatusBadRequest, err
+	}
+
+	if _, err := db.Queries.GetCommunityById(c, communityID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LeaderboardDTO{}, http.StatusNotFound, fmt.Errorf("community of id %s not found", communityID)
+		}
+		logger.Log.Errorf("failed to get community %s, %s", communityID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	rows, err := db.Queries.GetCommunityLeaderboard(c, models.GetCommunityLeaderboardParams{
+		Since:       since,
+		CommunityID: communityID,
+		RowLimit:    int32(limit),
+		RowOffset:   int32(offset),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get leaderboard of community %s, %s", communityID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+
+	leaderboard := dto.LeaderboardDTO{Period: period, Entries: make([]dto.LeaderboardEntryDTO, len(rows))}
+	for i, row := range rows {
+		leaderboard.Entries[i] = dto.LeaderboardEntryDTO{
+			Rank:              int(row.Rank),
+			UserID:            row.UserID,
+			Username:          row.Username.String,
+			Name:              row.Name.String,
+			ProfilePictureUrl: row.ProfilePictureUrl.String,
+			Color:             row.Color,
+			Score:             row.TotalScore,
+			QuizzesCompleted:  int(row.QuizzesCompleted),
+			CompletionSeconds: row.CompletionSeconds,
+		}
+	}
+
+	position, err := db.Queries.GetCommunityLeaderboardPosition(c, models.GetCommunityLeaderboardPositionParams{
+		Since:       since,
+		CommunityID: communityID,
+		UserID:      user.AppUser.UserID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Errorf("failed to get leaderboard position on community %s, %s", communityID, err.Error())
+		return dto.LeaderboardDTO{}, http.StatusInternalServerError, err
+	}
+	if err == nil {
+		me := ownLeaderboardEntry(
+			user.AppUser,
+			position.Rank,
+			position.TotalScore,
+			position.QuizzesCompleted,
+			position.CompletionSeconds,
+		)
+		leaderboard.Me = &me
+	}
+
+	return leaderboard, http.StatusAccepted, nil
+}
+
+func ownLeaderboardEntry(
+	user *dto.AppUserDataDTO,
+	rank int64,
+	score float64,
+	quizzesCompleted int32,
+	completionSeconds int64,
+) dto.LeaderboardEntryDTO {
+	return dto.LeaderboardEntryDTO{
+		Rank:              int(rank),
+		UserID:            user.UserID,
+		Username:          user.Username,
+		Name:              user.Name,
+		ProfilePictureUrl: user.ProfilePictureUrl,
+		Color:             user.Color,
+		Score:             score,
+		QuizzesCompleted:  int(quizzesCompleted),
+		CompletionSeconds: completionSeconds,
+	}
+}
+
+// periodStart is when the attempts counted by a board begin, all time boards
+// count every attempt
+func periodStart(period string, now time.Time) (time.Time, error) {
+	switch period {
+	case LeaderboardPeriodWeek:
+		return weekStart(now), nil
+	case LeaderboardPeriodAllTime:
+		return time.Time{}, nil
+	}
+	return time.Time{}, fmt.Errorf(
+		"period must be %s or %s",
+		LeaderboardPeriodWeek,
+		LeaderboardPeriodAllTime,
+	)
+}
+
+// weekStart returns the Monday midnight, UTC, of the week holding t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// completionSeconds is how long a session took, late sessions count up to
+// their deadline
+func completionSeconds(startedAt time.Time, submittedAt time.Time) int32 {
+	elapsed := submittedAt.Sub(startedAt)
+	if elapsed < 0 {
+		return 0
+	}
+	return int32(elapsed / time.Second)
+}
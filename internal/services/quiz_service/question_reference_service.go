@@ -0,0 +1,164 @@
+package quizservice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/easc01/mindo-server/internal/models"
+	topicrepository "github.com/easc01/mindo-server/internal/repository/topic_repository"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	contentutil "github.com/easc01/mindo-server/pkg/utils/content_util"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxQuestionReferences bounds the links kept per question
+	maxQuestionReferences = 3
+	// maxExplanationLength bounds the explanation kept per question
+	maxExplanationLength = 2000
+)
+
+const (
+	referenceTypeVideo         = "video"
+	referenceTypeStudyMaterial = "study_material"
+)
+
+// questionSources is what generated questions of a quiz may reference, quizzes
+// not tied to a topic have none
+type questionSources struct {
+	topicID  uuid.UUID
+	videos   []dto.VideoDataDTO
+	headings []string
+	hasNotes bool
+}
+
+// getQuestionSources collects the visible videos and notes headings of a topic
+func getQuestionSources(ctx context.Context, topic topicrepository.GetTopicByIDWithVideosRow) (questionSources, error) {
+	sources := questionSources{topicID: topic.ID}
+	for _, video := range topic.Videos {
+		if !video.IsHidden {
+			sources.videos = append(sources.videos, video)
+		}
+	}
+
+	material, err := db.Queries.GetStudyMaterialByTopicId(ctx, models.GetStudyMaterialByTopicIdParams{
+		TopicID: topic.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return sources, nil
+	}
+	if err != nil {
+		return questionSources{}, err
+	}
+
+	sources.hasNotes = true
+	sources.headings = contentutil.Headings(material.Content.String)
+	return sources, nil
+}
+
+// withSources tells the generator to explain its answers and what it may link
+func (s questionSources) withSources(params dto.GenerateQuizParams) dto.GenerateQuizParams {
+	params.IncludeExplanations = true
+	for _, video := range s.videos {
+		params.Videos = append(params.Videos, dto.GenerateQuizVideo{
+			VideoID:         video.VideoID,
+			Title:           video.Title,
+			DurationSeconds: video.DurationSeconds,
+		})
+	}
+	params.StudyMaterialHeadings = s.headings
+	return params
+}
+
+// readReferences keeps the generated references that point at something the
+// topic has, a made up link is dropped rather than costing the question
+func (s questionSources) readReferences(generated []dto.GeneratedQuizReference) []dto.QuizReferenceDTO {
+	references := []dto.QuizReferenceDTO{}
+
+	for _, reference := range generated {
+		if len(references) == maxQuestionReferences {
+			break
+		}
+
+		switch reference.Type {
+		case referenceTypeVideo:
+			video, ok := s.findVideo(reference.VideoID)
+			if !ok || reference.StartSeconds < 0 {
+				continue
+			}
+			if video.DurationSeconds > 0 && reference.StartSeconds >= video.DurationSeconds {
+				continue
+			}
+			references = append(references, dto.QuizReferenceDTO{
+				Type:         referenceTypeVideo,
+				VideoID:      video.VideoID,
+				Provider:     video.Provider,
+				StartSeconds: reference.StartSeconds,
+				Title:        video.Title,
+			})
+
+		case referenceTypeStudyMaterial:
+			if !s.hasNotes {
+				continue
+			}
+			heading, ok := s.findHeading(reference.Heading)
+			if !ok {
+				continue
+			}
+			topicID := s.topicID
+			references = append(references, dto.QuizReferenceDTO{
+				Type:    referenceTypeStudyMaterial,
+				TopicID: &topicID,
+				Heading: heading,
+			})
+		}
+	}
+
+	return references
+}
+
+func (s questionSources) findVideo(videoID string) (dto.VideoDataDTO, bool) {
+	for _, video := range s.videos {
+		if video.VideoID == videoID {
+			return video, true
+		}
+	}
+	return dto.VideoDataDTO{}, false
+}
+
+// findHeading matches a heading regardless of case and punctuation, no heading
+// links the notes as a whole
+func (s questionSources) findHeading(heading string) (string, bool) {
+	wanted := normalizeText(heading)
+	if wanted == "" {
+		return "", true
+	}
+	for _, candidate := range s.headings {
+		if normalizeText(candidate) == wanted {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func readExplanation(explanation string) sql.NullString {
+	explanation = strings.TrimSpace(explanation)
+	if explanation == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: contentutil.Excerpt(explanation, maxExplanationLength), Valid: true}
+}
+
+func readQuestionReferences(question models.QuizQuestion) []dto.QuizReferenceDTO {
+	var references []dto.QuizReferenceDTO
+	if len(question.ReferenceLinks) > 0 {
+		// links are validated before they are stored, a broken list only
+		// hides them
+		_ = json.Unmarshal(question.ReferenceLinks, &references)
+	}
+	return references
+}
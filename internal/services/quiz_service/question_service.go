@@ -0,0 +1,466 @@
+package quizservice
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/google/uuid"
+)
+
+// maxTextAnswerLength bounds short text answers a learner can send
+const maxTextAnswerLength = 500
+
+// QuestionTypes are the question types the generator may produce
+var QuestionTypes = []string{
+	string(models.QuizQuestionTypeSingleChoice),
+	string(models.QuizQuestionTypeMultiSelect),
+	string(models.QuizQuestionTypeTrueFalse),
+	string(models.QuizQuestionTypeNumeric),
+	string(models.QuizQuestionTypeShortText),
+	string(models.QuizQuestionTypeOrdering),
+}
+
+var trueFalseOptions = []string{"True", "False"}
+
+// givenAnswer is a learner's answer by original option numbers, it is what
+// sessions autosave and attempts store
+type givenAnswer struct {
+	Option  int      `json:"option,omitempty"`
+	Options []int    `json:"options,omitempty"`
+	Value   *float64 `json:"value,omitempty"`
+	Text    string   `json:"text,omitempty"`
+}
+
+// UnmarshalJSON also reads a bare option number, the shape sessions autosaved
+// before questions had types
+func (a *givenAnswer) UnmarshalJSON(data []byte) error {
+	var option int
+	if err := json.Unmarshal(data, &option); err == nil {
+		*a = givenAnswer{Option: option}
+		return nil
+	}
+
+	type plain givenAnswer
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+func isBlankAnswer(answer givenAnswer) bool {
+	return answer.Option == 0 && len(answer.Options) == 0 && answer.Value == nil && answer.Text == ""
+}
+
+func hasOptions(questionType models.QuizQuestionType) bool {
+	switch questionType {
+	case models.QuizQuestionTypeNumeric, models.QuizQuestionTypeShortText:
+		return false
+	}
+	return true
+}
+
+// isShuffled tells whether options are served in random order, true/false
+// always reads true first
+func isShuffled(questionType models.QuizQuestionType) bool {
+	return hasOptions(questionType) && questionType != models.QuizQuestionTypeTrueFalse
+}
+
+func readAnswerKey(question models.QuizQuestion) dto.QuizAnswerKey {
+	var key dto.QuizAnswerKey
+	if len(question.Answer) > 0 {
+		// keys are validated before they are stored, a broken one grades
+		// every answer wrong rather than failing the attempt
+		_ = json.Unmarshal(question.Answer, &key)
+	}
+	return key
+}
+
+// readAttemptedAnswers validates the answers of a submission against the
+// served questions and maps them to original option numbers. A question may
+// be skipped but not answered twice or from outside the session
+func readAttemptedAnswers(
+	submitted []dto.VerifyQuizQuestion,
+	served []models.QuizQuestion,
+	orders optionOrders,
+) (map[uuid.UUID]givenAnswer, error) {
+	byID := make(map[uuid.UUID]models.QuizQuestion, len(served))
+	for _, question := range served {
+		byID[question.ID] = question
+	}
+
+	attempted := make(map[uuid.UUID]givenAnswer, len(submitted))
+	for _, answer := range submitted {
+		questionID, err := uuid.Parse(answer.QuestionId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question id %q", answer.QuestionId)
+		}
+
+		question, ok := byID[questionID]
+		if !ok {
+			return nil, fmt.Errorf("question %s was not served in this session", questionID)
+		}
+		if _, ok := attempted[questionID]; ok {
+			return nil, fmt.Errorf("question %s is answered more than once", questionID)
+		}
+
+		given, err := readGivenAnswer(question, answer, orders)
+		if err != nil {
+			return nil, fmt.Errorf("question %s, %w", questionID, err)
+		}
+
+		attempted[questionID] = given
+	}
+
+	return attempted, nil
+}
+
+func readGivenAnswer(
+	question models.QuizQuestion,
+	answer dto.VerifyQuizQuestion,
+	orders optionOrders,
+) (givenAnswer, error) {
+	optionCount := len(question.Options)
+
+	switch question.QuestionType {
+	case models.QuizQuestionTypeMultiSelect, models.QuizQuestionTypeOrdering:
+		if len(answer.AttemptedOptions) == 0 {
+			return givenAnswer{}, errors.New("attemptedOptions is required")
+		}
+
+		seen := make(map[int]bool, len(answer.AttemptedOptions))
+		options := make([]int, len(answer.AttemptedOptions))
+		for i, option := range answer.AttemptedOptions {
+			if option < 1 || option > optionCount {
+				return givenAnswer{}, fmt.Errorf("option %d does not exist", option)
+			}
+			if seen[option] {
+				return givenAnswer{}, fmt.Errorf("option %d is given more than once", option)
+			}
+			seen[option] = true
+			options[i] = orders.toOriginal(question.ID, option)
+		}
+
+		if question.QuestionType == models.QuizQuestionTypeOrdering && len(options) != optionCount {
+			return givenAnswer{}, fmt.Errorf("all %d options must be ordered", optionCount)
+		}
+
+		return givenAnswer{Options: options}, nil
+
+	case models.QuizQuestionTypeNumeric:
+		if answer.Value == nil || math.IsNaN(*answer.Value) || math.IsInf(*answer.Value, 0) {
+			return givenAnswer{}, errors.New("value must be a number")
+		}
+		return givenAnswer{Value: answer.Value}, nil
+
+	case models.QuizQuestionTypeShortText:
+		text := strings.TrimSpace(answer.Text)
+		if text == "" {
+			return givenAnswer{}, errors.New("text is required")
+		}
+		if len([]rune(text)) > maxTextAnswerLength {
+			return givenAnswer{}, fmt.Errorf("text must be at most %d characters", maxTextAnswerLength)
+		}
+		return givenAnswer{Text: text}, nil
+
+	default:
+		if answer.AttemptedOption < 1 || answer.AttemptedOption > optionCount {
+			return givenAnswer{}, fmt.Errorf("option %d does not exist", answer.AttemptedOption)
+		}
+		return givenAnswer{Option: orders.toOriginal(question.ID, answer.AttemptedOption)}, nil
+	}
+}
+
+// isCorrectAnswer grades an answer by the rules of the question type
+func isCorrectAnswer(question models.QuizQuestion, given givenAnswer) bool {
+	key := readAnswerKey(question)
+
+	switch question.QuestionType {
+	case models.QuizQuestionTypeMultiSelect:
+		if len(given.Options) != len(key.Options) {
+			return false
+		}
+		expected := make(map[int]bool, len(key.Options))
+		for _, option := range key.Options {
+			expected[option] = true
+		}
+		for _, option := range given.Options {
+			if !expected[option] {
+				return false
+			}
+		}
+		return true
+
+	case models.QuizQuestionTypeOrdering:
+		if len(given.Options) != len(key.Options) {
+			return false
+		}
+		for i := range key.Options {
+			if given.Options[i] != key.Options[i] {
+				return false
+			}
+		}
+		return true
+
+	case models.QuizQuestionTypeNumeric:
+		if given.Value == nil || key.Value == nil {
+			return false
+		}
+		// a relative epsilon keeps float rounding, 0.1+0.2 against 0.3, from
+		// failing answers equal in value
+		tolerance := max(key.Tolerance, numericEpsilon*max(1, math.Abs(*key.Value)))
+		return math.Abs(*given.Value-*key.Value) <= tolerance
+
+	case models.QuizQuestionTypeShortText:
+		text := normalizeText(given.Text)
+		if text == "" {
+			return false
+		}
+		for _, accepted := range key.Accepted {
+			if normalizeText(accepted) == text {
+				return true
+			}
+		}
+		if key.Pattern != "" {
+			pattern, err := answerPattern(key.Pattern)
+			return err == nil && pattern.MatchString(strings.TrimSpace(given.Text))
+		}
+		return false
+
+	default:
+		return given.Option != 0 && given.Option == int(question.CorrectOption.Int32)
+	}
+}
+
+// numericEpsilon is the relative tolerance of numeric answers without one
+const numericEpsilon = 1e-9
+
+// answerPattern compiles the pattern of a short text key, it must match the
+// whole answer so a short pattern doesn't accept any text that contains it
+func answerPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)^(?:" + pattern + ")$")
+}
+
+// revealAnswer puts an answer next to the key in the option numbers the
+// learner was served
+func revealAnswer(question models.QuizQuestion, given givenAnswer, orders optionOrders) dto.QuizAnswerRevealDTO {
+	key := readAnswerKey(question)
+	servedOptions := func(options []int) []int {
+		if options == nil {
+			return nil
+		}
+		served := make([]int, len(options))
+		for i, option := range options {
+			served[i] = orders.toServed(question.ID, option)
+		}
+		return served
+	}
+
+	switch question.QuestionType {
+	case models.QuizQuestionTypeMultiSelect, models.QuizQuestionTypeOrdering:
+		return dto.QuizAnswerRevealDTO{
+			AttemptedOptions: servedOptions(given.Options),
+			CorrectOptions:   servedOptions(key.Options),
+		}
+
+	case models.QuizQuestionTypeNumeric:
+		return dto.QuizAnswerRevealDTO{
+			Value:        given.Value,
+			CorrectValue: key.Value,
+			Tolerance:    key.Tolerance,
+		}
+
+	case models.QuizQuestionTypeShortText:
+		return dto.QuizAnswerRevealDTO{
+			Text:            given.Text,
+			AcceptedAnswers: key.Accepted,
+		}
+
+	default:
+		return dto.QuizAnswerRevealDTO{
+			AttemptedOption: orders.toServed(question.ID, given.Option),
+			CorrectOption:   orders.toServed(question.ID, int(question.CorrectOption.Int32)),
+		}
+	}
+}
+
+// servedAnswer turns a saved answer back into the numbering the learner saw
+func servedAnswer(question models.QuizQuestion, given givenAnswer, orders optionOrders) dto.VerifyQuizQuestion {
+	answer := dto.VerifyQuizQuestion{
+		QuestionId:      question.ID.String(),
+		AttemptedOption: orders.toServed(question.ID, given.Option),
+		Value:           given.Value,
+		Text:            given.Text,
+	}
+	for _, option := range given.Options {
+		answer.AttemptedOptions = append(answer.AttemptedOptions, orders.toServed(question.ID, option))
+	}
+	return answer
+}
+
+// normalizeText makes short text answers compare regardless of case,
+// punctuation and spacing
+func normalizeText(text string) string {
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return ' '
+		}
+		return unicode.ToLower(r)
+	}, text)
+	return strings.Join(strings.Fields(stripped), " ")
+}
+
+// toQuestionParams validates a generated question against its declared type
+// and builds the row storing it along with its explanation
+func toQuestionParams(
+	quizID uuid.UUID,
+	question dto.GeneratedQuizQuestion,
+	sources questionSources,
+) (models.SaveQuizQuestionParams, error) {
+	questionType := models.QuizQuestionType(question.Type)
+	if questionType == "" {
+		questionType = models.QuizQuestionTypeSingleChoice
+	}
+
+	if strings.TrimSpace(question.Question) == "" {
+		return models.SaveQuizQuestionParams{}, errors.New("question text is empty")
+	}
+
+	options := make([]string, 0, len(question.Options))
+	for _, option := range question.Options {
+		text := strings.TrimSpace(option.Option)
+		if text == "" {
+			return models.SaveQuizQuestionParams{}, errors.New("an option is empty")
+		}
+		options = append(options, text)
+	}
+
+	key := dto.QuizAnswerKey{}
+	correctOption := sql.NullInt32{}
+
+	switch questionType {
+	case models.QuizQuestionTypeSingleChoice:
+		if len(options) < 2 {
+			return models.SaveQuizQuestionParams{}, errors.New("single choice needs at least 2 options")
+		}
+		if question.CorrectOption < 1 || question.CorrectOption > len(options) {
+			return models.SaveQuizQuestionParams{}, fmt.Errorf("correct option %d does not exist", question.CorrectOption)
+		}
+		correctOption = util.GetSQLNullInt32(question.CorrectOption)
+
+	case models.QuizQuestionTypeTrueFalse:
+		if len(options) == 0 {
+			options = trueFalseOptions
+		}
+		if len(options) != 2 {
+			return models.SaveQuizQuestionParams{}, errors.New("true/false needs exactly 2 options")
+		}
+		if question.CorrectOption < 1 || question.CorrectOption > 2 {
+			return models.SaveQuizQuestionParams{}, fmt.Errorf("correct option %d does not exist", question.CorrectOption)
+		}
+		correctOption = util.GetSQLNullInt32(question.CorrectOption)
+
+	case models.QuizQuestionTypeMultiSelect:
+		if len(options) < 2 {
+			return models.SaveQuizQuestionParams{}, errors.New("multi select needs at least 2 options")
+		}
+		if len(question.CorrectOptions) == 0 {
+			return models.SaveQuizQuestionParams{}, errors.New("multi select needs correct options")
+		}
+		if err := checkOptionNumbers(question.CorrectOptions, len(options)); err != nil {
+			return models.SaveQuizQuestionParams{}, err
+		}
+		key.Options = question.CorrectOptions
+
+	case models.QuizQuestionTypeOrdering:
+		if len(options) < 2 {
+			return models.SaveQuizQuestionParams{}, errors.New("ordering needs at least 2 options")
+		}
+		// options listed in their correct order need no separate sequence
+		key.Options = question.CorrectOptions
+		if len(key.Options) == 0 {
+			for i := range options {
+				key.Options = append(key.Options, i+1)
+			}
+		}
+		if len(key.Options) != len(options) {
+			return models.SaveQuizQuestionParams{}, errors.New("ordering sequence must cover every option")
+		}
+		if err := checkOptionNumbers(key.Options, len(options)); err != nil {
+			return models.SaveQuizQuestionParams{}, err
+		}
+
+	case models.QuizQuestionTypeNumeric:
+		value := question.NumericAnswer
+		if value == nil || math.IsNaN(*value) || math.IsInf(*value, 0) {
+			return models.SaveQuizQuestionParams{}, errors.New("numeric answer is missing")
+		}
+		if question.Tolerance < 0 {
+			return models.SaveQuizQuestionParams{}, errors.New("tolerance is negative")
+		}
+		options = nil
+		key.Value = value
+		key.Tolerance = question.Tolerance
+
+	case models.QuizQuestionTypeShortText:
+		for _, accepted := range question.AcceptedAnswers {
+			if normalizeText(accepted) != "" {
+				key.Accepted = append(key.Accepted, strings.TrimSpace(accepted))
+			}
+		}
+		if question.Pattern != "" {
+			if _, err := answerPattern(question.Pattern); err != nil {
+				return models.SaveQuizQuestionParams{}, fmt.Errorf("pattern does not compile, %w", err)
+			}
+			key.Pattern = question.Pattern
+		}
+		if len(key.Accepted) == 0 && key.Pattern == "" {
+			return models.SaveQuizQuestionParams{}, errors.New("short text needs accepted answers or a pattern")
+		}
+		options = nil
+
+	default:
+		return models.SaveQuizQuestionParams{}, fmt.Errorf("unknown question type %q", question.Type)
+	}
+
+	answer, err := json.Marshal(key)
+	if err != nil {
+		return models.SaveQuizQuestionParams{}, err
+	}
+
+	references, err := json.Marshal(sources.readReferences(question.References))
+	if err != nil {
+		return models.SaveQuizQuestionParams{}, err
+	}
+
+	return models.SaveQuizQuestionParams{
+		QuizID:         quizID,
+		QuestionType:   questionType,
+		Question:       util.GetSQLNullString(strings.TrimSpace(question.Question)),
+		Options:        options,
+		CorrectOption:  correctOption,
+		Answer:         answer,
+		Explanation:    readExplanation(question.Explanation),
+		ReferenceLinks: references,
+		UpdatedBy:      uuid.NullUUID{Valid: false},
+	}, nil
+}
+
+func checkOptionNumbers(numbers []int, optionCount int) error {
+	seen := make(map[int]bool, len(numbers))
+	for _, number := range numbers {
+		if number < 1 || number > optionCount {
+			return fmt.Errorf("option %d does not exist", number)
+		}
+		if seen[number] {
+			return fmt.Errorf("option %d is listed more than once", number)
+		}
+		seen[number] = true
+	}
+	return nil
+}
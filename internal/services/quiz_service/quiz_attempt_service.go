@@ -0,0 +1,229 @@
+package quizservice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QuizQuestionCount is how many questions a generated quiz asks
+const QuizQuestionCount = 10
+
+var errQuizSessionSubmitted = errors.New("quiz session is already submitted")
+
+type quizAnswer struct {
+	questionID uuid.UUID
+	answer     givenAnswer
+	isCorrect  bool
+}
+
+type quizAttempt struct {
+	marks          float64
+	grade          string
+	correctAnswers int
+	totalQuestions int
+	answers        []quizAnswer
+}
+
+// saveQuizAttempt stores a graded attempt with its answers, counts the play,
+// enters a first attempt on the leaderboards and closes the session, all or
+// nothing
+func saveQuizAttempt(
+	ctx context.Context,
+	session models.QuizSession,
+	submittedAt time.Time,
+	attempt quizAttempt,
+) (models.QuizResult, error) {
+	userID := session.UserID
+	quizID := session.QuizID
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return models.QuizResult{}, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	result, err := qtx.CreateQuizResult(ctx, models.CreateQuizResultParams{
+		QuizID:         quizID,
+		UserID:         userID,
+		Score:          util.GetSQLNullString(strconv.FormatFloat(attempt.marks, 'f', 2, 64)),
+		Grade:          util.GetSQLNullString(attempt.grade),
+		CorrectAnswers: int32(attempt.correctAnswers),
+		TotalQuestions: int32(attempt.totalQuestions),
+	})
+	if err != nil {
+		tx.Rollback()
+		return models.QuizResult{}, err
+	}
+
+	for _, answer := range attempt.answers {
+		attemptedAnswer, err := json.Marshal(answer.answer)
+		if err != nil {
+			tx.Rollback()
+			return models.QuizResult{}, err
+		}
+
+		if err := qtx.CreateQuizResultQuestion(ctx, models.CreateQuizResultQuestionParams{
+			QuizResultID:    result.ID,
+			QuizQuestionID:  answer.questionID,
+			AttemptedOption: util.GetSQLNullInt32(answer.answer.Option),
+			AttemptedAnswer: attemptedAnswer,
+			IsCorrect:       answer.isCorrect,
+			UpdatedBy:       util.GetNullUUID(userID),
+		}); err != nil {
+			tx.Rollback()
+			return models.QuizResult{}, err
+		}
+	}
+
+	// a concurrent submit of the same session loses here and rolls back
+	submitted, err := qtx.SubmitQuizSession(ctx, models.SubmitQuizSessionParams{
+		ID:           session.ID,
+		QuizResultID: util.GetNullUUID(result.ID),
+		SubmittedAt:  sql.NullTime{Time: submittedAt, Valid: true},
+	})
+	if err != nil {
+		tx.Rollback()
+		return models.QuizResult{}, err
+	}
+	if submitted == 0 {
+		tx.Rollback()
+		return models.QuizResult{}, errQuizSessionSubmitted
+	}
+
+	if err := qtx.IncrementQuizPlayCount(ctx, quizID); err != nil {
+		tx.Rollback()
+		return models.QuizResult{}, err
+	}
+
+	if err := qtx.InsertQuizLeaderboardEntry(ctx, models.InsertQuizLeaderboardEntryParams{
+		UserID:            userID,
+		QuizResultID:      result.ID,
+		Score:             attempt.marks,
+		CompletionSeconds: completionSeconds(session.StartedAt, submittedAt),
+		SubmittedAt:       submittedAt,
+		QuizID:            quizID,
+	}); err != nil {
+		tx.Rollback()
+		return models.QuizResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.QuizResult{}, err
+	}
+
+	return result, nil
+}
+
+// GetQuizAttempts returns the learner's past attempts, latest first
+func GetQuizAttempts(c *gin.Context, limit int, offset int) ([]dto.QuizAttemptDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return []dto.QuizAttemptDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	rows, err := db.Queries.GetQuizResultsByUserId(c, models.GetQuizResultsByUserIdParams{
+		UserID: user.AppUser.UserID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to get quiz attempts of user %s, %s", user.AppUser.UserID, err.Error())
+		return []dto.QuizAttemptDTO{}, http.StatusInternalServerError, err
+	}
+
+	attempts := make([]dto.QuizAttemptDTO, len(rows))
+	for i, row := range rows {
+		attempts[i] = serializeQuizAttempt(models.GetQuizResultByIdRow(row))
+	}
+
+	return attempts, http.StatusAccepted, nil
+}
+
+// GetQuizAttemptById returns one of the learner's attempts with every answer
+// and whether it was right
+func GetQuizAttemptById(c *gin.Context, attemptID uuid.UUID) (dto.QuizAttemptDetailsDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizAttemptDetailsDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	result, err := db.Queries.GetQuizResultById(c, models.GetQuizResultByIdParams{
+		ID:     attemptID,
+		UserID: user.AppUser.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizAttemptDetailsDTO{}, http.StatusNotFound, fmt.Errorf("quiz attempt of id %s not found", attemptID)
+		}
+		logger.Log.Errorf("failed to get quiz attempt %s, %s", attemptID, err.Error())
+		return dto.QuizAttemptDetailsDTO{}, http.StatusInternalServerError, err
+	}
+
+	answers, err := db.Queries.GetQuizResultQuestions(c, attemptID)
+	if err != nil {
+		logger.Log.Errorf("failed to get answers of quiz attempt %s, %s", attemptID, err.Error())
+		return dto.QuizAttemptDetailsDTO{}, http.StatusInternalServerError, err
+	}
+
+	questions := make([]dto.QuizAttemptQuestionDTO, len(answers))
+	for i, answer := range answers {
+		question := models.QuizQuestion{
+			ID:            answer.ID,
+			QuestionType:  answer.QuestionType,
+			Options:       answer.Options,
+			CorrectOption: answer.CorrectOption,
+			Answer:        answer.Answer,
+		}
+
+		// attempts stored before questions had types only kept the option
+		var given givenAnswer
+		if err := json.Unmarshal(answer.AttemptedAnswer, &given); err != nil || isBlankAnswer(given) {
+			given = givenAnswer{Option: int(answer.AttemptedOption.Int32)}
+		}
+
+		questions[i] = dto.QuizAttemptQuestionDTO{
+			QuestionID:          answer.ID,
+			Type:                string(answer.QuestionType),
+			Question:            answer.Question.String,
+			Options:             answer.Options,
+			QuizAnswerRevealDTO: revealAnswer(question, given, nil),
+			IsCorrect:           answer.IsCorrect,
+		}
+	}
+
+	return dto.QuizAttemptDetailsDTO{
+		QuizAttemptDTO: serializeQuizAttempt(result),
+		Questions:      questions,
+	}, http.StatusAccepted, nil
+}
+
+func serializeQuizAttempt(result models.GetQuizResultByIdRow) dto.QuizAttemptDTO {
+	marks, _ := strconv.ParseFloat(result.Score.String, 64)
+
+	return dto.QuizAttemptDTO{
+		ID:             result.ID,
+		QuizID:         result.QuizID,
+		QuizName:       result.QuizName.String,
+		Marks:          marks,
+		Grade:          result.Grade.String,
+		CorrectAnswers: int(result.CorrectAnswers),
+		TotalQuestions: int(result.TotalQuestions),
+		AttemptedAt:    result.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,185 @@
+package quizservice
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type quizScore struct {
+	obtainedMarks float64
+	totalMarks    float64
+	percentage    float64
+	grade         string
+}
+
+// scoreQuiz weighs every served question, wrong answers lose the negative
+// marking share of their weight while skipped ones lose nothing. The
+// percentage never drops below zero
+func scoreQuiz(quiz models.Quiz, served []models.QuizQuestion, answers []quizAnswer) quizScore {
+	weights := make(map[uuid.UUID]float64, len(served))
+	score := quizScore{}
+
+	for _, question := range served {
+		weights[question.ID] = question.Weight
+		score.totalMarks += question.Weight
+	}
+
+	for _, answer := range answers {
+		if answer.isCorrect {
+			score.obtainedMarks += weights[answer.questionID]
+		} else {
+			score.obtainedMarks -= weights[answer.questionID] * quiz.NegativeMarking
+		}
+	}
+
+	if score.totalMarks > 0 {
+		score.percentage = math.Max(score.obtainedMarks, 0) / score.totalMarks * 100
+	}
+	score.grade = util.GetGradeForPercentage(score.percentage, gradeBands(quiz))
+
+	return score
+}
+
+// gradeBands returns the bands configured on the quiz, a quiz without its own
+// bands is graded on the defaults
+func gradeBands(quiz models.Quiz) []util.GradeBand {
+	var bands []util.GradeBand
+	if err := json.Unmarshal(quiz.GradeBands, &bands); err != nil {
+		logger.Log.Errorf("failed to read grade bands of quiz %s, %s", quiz.ID, err.Error())
+		return util.DefaultGradeBands
+	}
+	if len(bands) == 0 {
+		return util.DefaultGradeBands
+	}
+	return bands
+}
+
+// UpdateQuizScoring sets the negative marking, grade bands and question weights
+// of a quiz, attempts already graded keep their score
+func UpdateQuizScoring(
+	c *gin.Context,
+	quizID uuid.UUID,
+	req dto.UpdateQuizScoringRequest,
+) (dto.QuizScoringDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.QuizScoringDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	bands, err := validateGradeBands(req.GradeBands)
+	if err != nil {
+		return dto.QuizScoringDTO{}, http.StatusBadRequest, err
+	}
+
+	encodedBands, err := json.Marshal(bands)
+	if err != nil {
+		return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+	}
+
+	tx, err := db.DB.BeginTx(c, nil)
+	if err != nil {
+		return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	quiz, err := qtx.UpdateQuizScoring(c, models.UpdateQuizScoringParams{
+		ID:              quizID,
+		NegativeMarking: *req.NegativeMarking,
+		GradeBands:      encodedBands,
+		UpdatedBy:       util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizScoringDTO{}, http.StatusNotFound, fmt.Errorf("quiz of id %s not found", quizID)
+		}
+		logger.Log.Errorf("failed to update scoring of quiz %s, %s", quizID, err.Error())
+		return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+	}
+
+	for _, weight := range req.QuestionWeights {
+		updated, err := qtx.UpdateQuizQuestionWeight(c, models.UpdateQuizQuestionWeightParams{
+			ID:        weight.QuestionID,
+			QuizID:    quizID,
+			Weight:    weight.Weight,
+			UpdatedBy: util.GetNullUUID(user.AdminUser.UserID),
+		})
+		if err != nil {
+			tx.Rollback()
+			logger.Log.Errorf("failed to update weight of question %s, %s", weight.QuestionID, err.Error())
+			return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+		}
+		if updated == 0 {
+			tx.Rollback()
+			return dto.QuizScoringDTO{}, http.StatusBadRequest, fmt.Errorf("question %s is not part of quiz %s", weight.QuestionID, quizID)
+		}
+	}
+
+	questions, err := qtx.GetQuestionsByQuizId(c, quizID)
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to get questions of quiz %s, %s", quizID, err.Error())
+		return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.QuizScoringDTO{}, http.StatusInternalServerError, err
+	}
+
+	weights := make([]dto.QuestionWeightDTO, len(questions))
+	for i, question := range questions {
+		weights[i] = dto.QuestionWeightDTO{
+			QuestionID: question.ID,
+			Weight:     question.Weight,
+		}
+	}
+
+	return dto.QuizScoringDTO{
+		QuizID:          quiz.ID,
+		NegativeMarking: quiz.NegativeMarking,
+		GradeBands:      gradeBands(quiz),
+		QuestionWeights: weights,
+	}, http.StatusAccepted, nil
+}
+
+// validateGradeBands needs distinct thresholds and a band starting at zero so
+// every score gets a grade, no bands at all means the defaults
+func validateGradeBands(requested []dto.GradeBandDTO) ([]util.GradeBand, error) {
+	bands := make([]util.GradeBand, 0, len(requested))
+	if len(requested) == 0 {
+		return bands, nil
+	}
+
+	thresholds := make(map[float64]bool, len(requested))
+	for _, band := range requested {
+		if thresholds[*band.MinPercent] {
+			return nil, fmt.Errorf("more than one grade band starts at %g%%", *band.MinPercent)
+		}
+		thresholds[*band.MinPercent] = true
+
+		bands = append(bands, util.GradeBand{
+			Grade:      band.Grade,
+			MinPercent: *band.MinPercent,
+		})
+	}
+
+	if !thresholds[0] {
+		return nil, fmt.Errorf("a grade band must start at 0%%")
+	}
+
+	return bands, nil
+}
@@ -1,27 +1,46 @@
 package quizservice
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/easc01/mindo-server/internal/middleware"
 	"github.com/easc01/mindo-server/internal/models"
 	aiservice "github.com/easc01/mindo-server/internal/services/ai_service"
+	certificateservice "github.com/easc01/mindo-server/internal/services/certificate_service"
+	flashcardservice "github.com/easc01/mindo-server/internal/services/flashcard_service"
 	"github.com/easc01/mindo-server/pkg/db"
 	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
 	"github.com/easc01/mindo-server/pkg/utils/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// GenerateAndSaveQuiz generates a quiz for the learner and opens a session
+// holding the served questions, correct options never leave the server
 func GenerateAndSaveQuiz(
 	c *gin.Context,
 	params dto.GenerateQuizParams,
-) (dto.GeneratedQuiz, error) {
-	generatedQuiz, err := aiservice.GenerateQuiz(params)
+) (dto.QuizDTO, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizDTO{}, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	// quizzes not tied to a topic have nothing to link, they still get
+	// explanations
+	sources := questionSources{}
+	generatedQuiz, err := aiservice.GenerateQuiz(sources.withSources(params))
 
 	if err != nil {
 		logger.Log.Errorf("failed to generate quiz - %s, because %s", params.TopicName, err.Error())
-		return dto.GeneratedQuiz{}, err
+		return dto.QuizDTO{}, err
 	}
 
 	savedQuiz, err := db.Queries.SaveQuiz(c, models.SaveQuizParams{
@@ -35,62 +54,219 @@ func GenerateAndSaveQuiz(
 			Valid: false,
 		},
 	})
+	if err != nil {
+		logger.Log.Errorf("failed to save quiz - %s, because %s", params.TopicName, err.Error())
+		return dto.QuizDTO{}, err
+	}
+
+	questions, err := saveGeneratedQuestions(c, db.Queries, savedQuiz.ID, generatedQuiz, sources)
+	if err != nil {
+		logger.Log.Errorf("failed to save questions of quiz %s, %s", savedQuiz.ID, err.Error())
+		return dto.QuizDTO{}, err
+	}
+
+	return openQuizSession(c, user.AppUser.UserID, savedQuiz, questions)
+}
+
+// saveGeneratedQuestions stores the questions that match their declared type,
+// the generator getting a few wrong does not cost the whole quiz
+func saveGeneratedQuestions(
+	ctx context.Context,
+	queries *models.Queries,
+	quizID uuid.UUID,
+	generatedQuiz dto.GeneratedQuiz,
+	sources questionSources,
+) ([]models.QuizQuestion, error) {
+	questions := make([]models.QuizQuestion, 0, len(generatedQuiz.Questions))
 
 	for i, question := range generatedQuiz.Questions {
-		options := make([]string, 0)
-		for _, option := range question.Options {
-			options = append(options, option.Option)
+		params, err := toQuestionParams(quizID, question, sources)
+		if err != nil {
+			logger.Log.Warnf("skipped generated question %d of quiz %s, %s", i+1, quizID, err.Error())
+			continue
 		}
 
-		ques, _ := db.Queries.SaveQuizQuestion(c, models.SaveQuizQuestionParams{
-			QuizID:        savedQuiz.ID,
-			Question:      util.GetSQLNullString(question.Question),
-			Options:       options,
-			CorrectOption: sql.NullInt32{Int32: int32(question.CorrectOption), Valid: true},
-			UpdatedBy:     uuid.NullUUID{Valid: false},
-		})
-		generatedQuiz.Questions[i].QuestionId = ques.ID.String()
+		ques, err := queries.SaveQuizQuestion(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, ques)
 	}
 
-	if err != nil {
-		return dto.GeneratedQuiz{}, err
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("generator returned no valid questions for quiz %s", quizID)
 	}
 
-	generatedQuiz.QuizId = savedQuiz.ID.String()
-	return generatedQuiz, nil
+	return questions, nil
 }
 
+// VerifyQuizResults grades a session once, only the questions served in it
+// count and their answers are revealed in the result, wrong ones along with
+// the explanation and where the topic covers them. Past the deadline the
+// submitted answers are ignored and the session is graded as it was autosaved
 func VerifyQuizResults(
 	c *gin.Context,
 	params dto.VerifyQuizParams,
-) (dto.VerifyQuizResults, error) {
-	quizUuid, _ := uuid.Parse(params.QuizId)
-	questions, err := db.Queries.GetQuestionsByQuizId(c, quizUuid)
+) (dto.VerifyQuizResults, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.VerifyQuizResults{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
 
-	if err != nil || len(questions) == 0 {
-		return dto.VerifyQuizResults{}, nil
+	sessionID, err := uuid.Parse(params.SessionId)
+	if err != nil {
+		return dto.VerifyQuizResults{}, http.StatusBadRequest, fmt.Errorf("invalid quiz session id")
 	}
 
-	correctResponses := 0
+	session, statusCode, err := getOpenQuizSession(c, sessionID, user.AppUser.UserID)
+	if err != nil {
+		return dto.VerifyQuizResults{}, statusCode, err
+	}
+	if params.QuizId != "" && params.QuizId != session.QuizID.String() {
+		return dto.VerifyQuizResults{}, http.StatusBadRequest, fmt.Errorf("quiz session %s does not belong to quiz %s", sessionID, params.QuizId)
+	}
 
-	for _, question := range params.Questions {
-		var questionData models.QuizQuestion
+	served, err := getServedQuestions(c, session)
+	if err != nil {
+		logger.Log.Errorf("failed to get questions of quiz session %s, %s", sessionID, err.Error())
+		return dto.VerifyQuizResults{}, http.StatusInternalServerError, err
+	}
+	if len(served) == 0 {
+		return dto.VerifyQuizResults{}, http.StatusUnprocessableEntity, fmt.Errorf("quiz %s has no questions to grade", session.QuizID)
+	}
+
+	orders, attempted, err := readSessionState(session)
+	if err != nil {
+		logger.Log.Errorf("failed to read state of quiz session %s, %s", sessionID, err.Error())
+		return dto.VerifyQuizResults{}, http.StatusInternalServerError, err
+	}
 
-		for _, ques := range questions {
+	now := time.Now().UTC()
+	isLate := isSessionExpired(session, now)
+	submittedAt := now
 
-			if question.QuestionId == ques.ID.String() {
-				questionData = ques
-				break
-			}
+	if isLate {
+		submittedAt = session.DeadlineAt.Time
+	} else {
+		submitted, err := readAttemptedAnswers(params.Questions, served, orders)
+		if err != nil {
+			return dto.VerifyQuizResults{}, http.StatusBadRequest, err
+		}
+		for questionID, answer := range submitted {
+			attempted[questionID] = answer
 		}
+	}
 
-		if question.AttemptedOption == int(questionData.CorrectOption.Int32) {
+	return gradeQuizSession(c, session, served, orders, attempted, submittedAt, isLate)
+}
+
+// gradeQuizSession scores the answers of a session by original option number
+// and closes it, answers are revealed in the numbering the learner was served
+func gradeQuizSession(
+	ctx context.Context,
+	session models.QuizSession,
+	served []models.QuizQuestion,
+	orders optionOrders,
+	attempted map[uuid.UUID]givenAnswer,
+	submittedAt time.Time,
+	isLate bool,
+) (dto.VerifyQuizResults, int, error) {
+	quiz, err := db.Queries.GetQuizById(ctx, session.QuizID)
+	if err != nil {
+		logger.Log.Errorf("failed to get quiz %s, %s", session.QuizID, err.Error())
+		return dto.VerifyQuizResults{}, http.StatusInternalServerError, err
+	}
+
+	correctResponses := 0
+	var answers []quizAnswer
+	var missed []models.QuizQuestion
+	revealed := make([]dto.VerifiedAnswerDTO, 0, len(served))
+
+	for _, questionData := range served {
+		given, answered := attempted[questionData.ID]
+		isCorrect := answered && isCorrectAnswer(questionData, given)
+		if isCorrect {
 			correctResponses++
+		} else {
+			missed = append(missed, questionData)
+		}
+
+		if answered {
+			answers = append(answers, quizAnswer{
+				questionID: questionData.ID,
+				answer:     given,
+				isCorrect:  isCorrect,
+			})
+		}
+
+		verified := dto.VerifiedAnswerDTO{
+			QuestionID:          questionData.ID,
+			Type:                string(questionData.QuestionType),
+			QuizAnswerRevealDTO: revealAnswer(questionData, given, orders),
+			IsCorrect:           isCorrect,
+		}
+		if !isCorrect {
+			verified.Explanation = questionData.Explanation.String
+			verified.References = readQuestionReferences(questionData)
+		}
+		revealed = append(revealed, verified)
+	}
+
+	score := scoreQuiz(quiz, served, answers)
+
+	result, err := saveQuizAttempt(ctx, session, submittedAt, quizAttempt{
+		marks:          score.percentage,
+		grade:          score.grade,
+		correctAnswers: correctResponses,
+		totalQuestions: len(served),
+		answers:        answers,
+	})
+	if err != nil {
+		if errors.Is(err, errQuizSessionSubmitted) {
+			return dto.VerifyQuizResults{}, http.StatusConflict, err
+		}
+		logger.Log.Errorf("failed to save attempt of quiz %s, %s", session.QuizID, err.Error())
+		return dto.VerifyQuizResults{}, http.StatusInternalServerError, err
+	}
+
+	// passing a topic quiz or the exam may complete the playlist, the result
+	// is saved either way
+	if err := issueEarnedCertificate(ctx, session.UserID, quiz); err != nil {
+		logger.Log.Errorf("failed to issue certificate after quiz %s, %s", quiz.ID, err.Error())
+	}
+
+	// missed questions come back as flashcards in the deck of the quiz they
+	// belong to, failing to add them must not cost the learner their result
+	missedByQuiz := make(map[uuid.UUID][]models.QuizQuestion)
+	for _, question := range missed {
+		missedByQuiz[question.QuizID] = append(missedByQuiz[question.QuizID], question)
+	}
+	for quizID, questions := range missedByQuiz {
+		if err := flashcardservice.AddMissedQuestions(ctx, session.UserID, quizID, questions); err != nil {
+			logger.Log.Errorf("failed to add missed questions of quiz %s to flashcards, %s", quizID, err.Error())
 		}
 	}
 
 	return dto.VerifyQuizResults{
-		Grade: util.GetGrade(10, correctResponses),
-		Marks: float64(correctResponses) / 10.00 * 100,
-	}, nil
+		AttemptID:     result.ID,
+		IsLate:        isLate,
+		Grade:         score.grade,
+		Marks:         score.percentage,
+		ObtainedMarks: score.obtainedMarks,
+		TotalMarks:    score.totalMarks,
+		Answers:       revealed,
+	}, http.StatusAccepted, nil
+}
+
+// issueEarnedCertificate issues the certificate of the playlist a quiz belongs
+// to once the learner is eligible, free quizzes belong to none
+func issueEarnedCertificate(ctx context.Context, userID uuid.UUID, quiz models.Quiz) error {
+	switch {
+	case quiz.TopicID.Valid:
+		return certificateservice.IssueEarnedTopicCertificate(ctx, userID, quiz.TopicID.UUID)
+	case quiz.PlaylistID.Valid:
+		return certificateservice.IssueEarnedCertificate(ctx, userID, quiz.PlaylistID.UUID)
+	}
+	return nil
 }
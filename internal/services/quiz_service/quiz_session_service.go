@@ -0,0 +1,434 @@
+package quizservice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// expiredSessionBatch bounds how many sessions one sweep grades
+const expiredSessionBatch = 100
+
+var errQuizSessionExpired = errors.New("quiz session time is up, it is graded as it was saved")
+
+// optionOrders holds, per question, the original option numbers in the order
+// they were served
+type optionOrders map[uuid.UUID][]int
+
+// toOriginal turns an option number the learner saw into the stored one
+func (o optionOrders) toOriginal(questionID uuid.UUID, served int) int {
+	order := o[questionID]
+	if served < 1 || served > len(order) {
+		return served
+	}
+	return order[served-1]
+}
+
+// toServed turns a stored option number into the one the learner saw
+func (o optionOrders) toServed(questionID uuid.UUID, original int) int {
+	for i, option := range o[questionID] {
+		if option == original {
+			return i + 1
+		}
+	}
+	return original
+}
+
+func shuffleOptions(questions []models.QuizQuestion) optionOrders {
+	orders := make(optionOrders, len(questions))
+	for _, question := range questions {
+		if !isShuffled(question.QuestionType) {
+			continue
+		}
+		order := make([]int, len(question.Options))
+		for i, option := range rand.Perm(len(question.Options)) {
+			order[i] = option + 1
+		}
+		orders[question.ID] = order
+	}
+	return orders
+}
+
+// openQuizSession records which questions the learner is served and in which
+// order their options appear, only those questions are graded. Quizzes with a
+// time limit get a deadline
+func openQuizSession(
+	ctx context.Context,
+	userID uuid.UUID,
+	quiz models.Quiz,
+	questions []models.QuizQuestion,
+) (dto.QuizDTO, error) {
+	questionIDs := make([]uuid.UUID, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.ID
+	}
+
+	orders := shuffleOptions(questions)
+	encodedOrders, err := json.Marshal(orders)
+	if err != nil {
+		return dto.QuizDTO{}, err
+	}
+
+	startedAt := time.Now().UTC()
+	deadlineAt := sql.NullTime{}
+	if quiz.TimeLimitSeconds.Valid {
+		deadlineAt = sql.NullTime{
+			Time:  startedAt.Add(time.Duration(quiz.TimeLimitSeconds.Int32) * time.Second),
+			Valid: true,
+		}
+	}
+
+	session, err := db.Queries.CreateQuizSession(ctx, models.CreateQuizSessionParams{
+		QuizID:       quiz.ID,
+		UserID:       userID,
+		QuestionIds:  questionIDs,
+		OptionOrders: encodedOrders,
+		StartedAt:    startedAt,
+		DeadlineAt:   deadlineAt,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to open session of quiz %s, %s", quiz.ID, err.Error())
+		return dto.QuizDTO{}, err
+	}
+
+	return serializeQuizSession(quiz, session, questions, orders, nil), nil
+}
+
+// GetQuizSession returns an open session as it was served along with its
+// autosaved answers, so a learner can resume it
+func GetQuizSession(c *gin.Context, sessionID uuid.UUID) (dto.QuizDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	session, statusCode, err := getOpenQuizSession(c, sessionID, user.AppUser.UserID)
+	if err != nil {
+		return dto.QuizDTO{}, statusCode, err
+	}
+
+	quiz, err := db.Queries.GetQuizById(c, session.QuizID)
+	if err != nil {
+		logger.Log.Errorf("failed to get quiz %s, %s", session.QuizID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	served, err := getServedQuestions(c, session)
+	if err != nil {
+		logger.Log.Errorf("failed to get questions of quiz session %s, %s", sessionID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	orders, drafts, err := readSessionState(session)
+	if err != nil {
+		logger.Log.Errorf("failed to read state of quiz session %s, %s", sessionID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	return serializeQuizSession(quiz, session, served, orders, drafts), http.StatusAccepted, nil
+}
+
+// SaveQuizAnswers autosaves answers of an open session, later saves of the
+// same question replace earlier ones
+func SaveQuizAnswers(
+	c *gin.Context,
+	sessionID uuid.UUID,
+	req dto.SaveQuizAnswersRequest,
+) (dto.QuizSessionDraftDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizSessionDraftDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	session, statusCode, err := getOpenQuizSession(c, sessionID, user.AppUser.UserID)
+	if err != nil {
+		return dto.QuizSessionDraftDTO{}, statusCode, err
+	}
+
+	now := time.Now().UTC()
+	if isSessionExpired(session, now) {
+		return dto.QuizSessionDraftDTO{}, http.StatusConflict, errQuizSessionExpired
+	}
+
+	served, err := getServedQuestions(c, session)
+	if err != nil {
+		logger.Log.Errorf("failed to get questions of quiz session %s, %s", sessionID, err.Error())
+		return dto.QuizSessionDraftDTO{}, http.StatusInternalServerError, err
+	}
+
+	orders, drafts, err := readSessionState(session)
+	if err != nil {
+		logger.Log.Errorf("failed to read state of quiz session %s, %s", sessionID, err.Error())
+		return dto.QuizSessionDraftDTO{}, http.StatusInternalServerError, err
+	}
+
+	submitted, err := readAttemptedAnswers(req.Questions, served, orders)
+	if err != nil {
+		return dto.QuizSessionDraftDTO{}, http.StatusBadRequest, err
+	}
+
+	for questionID, answer := range submitted {
+		drafts[questionID] = answer
+	}
+
+	encodedAnswers, err := json.Marshal(submitted)
+	if err != nil {
+		return dto.QuizSessionDraftDTO{}, http.StatusInternalServerError, err
+	}
+
+	updated, err := db.Queries.SaveQuizSessionAnswers(c, models.SaveQuizSessionAnswersParams{
+		ID:           sessionID,
+		UserID:       user.AppUser.UserID,
+		DraftAnswers: encodedAnswers,
+		Now:          now.Add(-config.GetConfig().QuizSubmitGrace),
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to save answers of quiz session %s, %s", sessionID, err.Error())
+		return dto.QuizSessionDraftDTO{}, http.StatusInternalServerError, err
+	}
+	if updated == 0 {
+		return dto.QuizSessionDraftDTO{}, http.StatusConflict, errQuizSessionSubmitted
+	}
+
+	return dto.QuizSessionDraftDTO{
+		SessionID:    sessionID,
+		SavedAnswers: len(drafts),
+		DeadlineAt:   nullTimePtr(session.DeadlineAt),
+	}, http.StatusAccepted, nil
+}
+
+// UpdateQuizTimeLimit sets how long sessions of a quiz may run, sessions
+// already open keep their deadline
+func UpdateQuizTimeLimit(
+	c *gin.Context,
+	quizID uuid.UUID,
+	req dto.UpdateQuizTimeLimitRequest,
+) (dto.QuizTimeLimitDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AdminUser == nil {
+		return dto.QuizTimeLimitDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAdminUserContext)
+	}
+
+	timeLimit := sql.NullInt32{}
+	if req.TimeLimitSeconds != nil {
+		timeLimit = util.GetSQLNullInt32(*req.TimeLimitSeconds)
+	}
+
+	quiz, err := db.Queries.UpdateQuizTimeLimit(c, models.UpdateQuizTimeLimitParams{
+		ID:               quizID,
+		TimeLimitSeconds: timeLimit,
+		UpdatedBy:        util.GetNullUUID(user.AdminUser.UserID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizTimeLimitDTO{}, http.StatusNotFound, fmt.Errorf("quiz of id %s not found", quizID)
+		}
+		logger.Log.Errorf("failed to update time limit of quiz %s, %s", quizID, err.Error())
+		return dto.QuizTimeLimitDTO{}, http.StatusInternalServerError, err
+	}
+
+	response := dto.QuizTimeLimitDTO{QuizID: quiz.ID}
+	if quiz.TimeLimitSeconds.Valid {
+		seconds := int(quiz.TimeLimitSeconds.Int32)
+		response.TimeLimitSeconds = &seconds
+	}
+
+	return response, http.StatusAccepted, nil
+}
+
+// FinalizeExpiredQuizSessions grades timed sessions past their deadline as they
+// were saved. Untimed sessions left open are graded when they hold answers and
+// closed without a result otherwise
+func FinalizeExpiredQuizSessions(ctx context.Context) error {
+	cfg := config.GetConfig()
+	now := time.Now().UTC()
+
+	sessions, err := db.Queries.GetExpiredQuizSessions(ctx, models.GetExpiredQuizSessionsParams{
+		DeadlineCutoff: sql.NullTime{Time: now.Add(-cfg.QuizSubmitGrace), Valid: true},
+		AbandonCutoff:  now.Add(-cfg.QuizSessionAbandonAfter),
+		RowLimit:       expiredSessionBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get expired quiz sessions, %w", err)
+	}
+
+	finalized := 0
+	for _, session := range sessions {
+		if err := finalizeQuizSession(ctx, session, now); err != nil {
+			logger.Log.Errorf("failed to finalize quiz session %s, %s", session.ID, err.Error())
+			continue
+		}
+		finalized++
+	}
+
+	if finalized > 0 {
+		logger.Log.Infof("finalized %d expired quiz sessions", finalized)
+	}
+
+	return nil
+}
+
+func finalizeQuizSession(ctx context.Context, session models.QuizSession, now time.Time) error {
+	orders, drafts, err := readSessionState(session)
+	if err != nil {
+		return err
+	}
+
+	served, err := getServedQuestions(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	if len(served) == 0 || (!session.DeadlineAt.Valid && len(drafts) == 0) {
+		_, err := db.Queries.SubmitQuizSession(ctx, models.SubmitQuizSessionParams{
+			ID:          session.ID,
+			SubmittedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		return err
+	}
+
+	submittedAt := now
+	if session.DeadlineAt.Valid {
+		submittedAt = session.DeadlineAt.Time
+	}
+
+	// the learner submitting at the same moment wins, there is nothing left
+	// to grade then
+	_, _, err = gradeQuizSession(ctx, session, served, orders, drafts, submittedAt, true)
+	if errors.Is(err, errQuizSessionSubmitted) {
+		return nil
+	}
+	return err
+}
+
+func getOpenQuizSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (models.QuizSession, int, error) {
+	session, err := db.Queries.GetQuizSessionById(ctx, models.GetQuizSessionByIdParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.QuizSession{}, http.StatusNotFound, fmt.Errorf("quiz session of id %s not found", sessionID)
+		}
+		logger.Log.Errorf("failed to get quiz session %s, %s", sessionID, err.Error())
+		return models.QuizSession{}, http.StatusInternalServerError, err
+	}
+	if session.SubmittedAt.Valid {
+		return models.QuizSession{}, http.StatusConflict, errQuizSessionSubmitted
+	}
+
+	return session, http.StatusOK, nil
+}
+
+// getServedQuestions returns the questions of a session in the order they were
+// served. Exam sessions serve questions of other quizzes, so they are looked
+// up by id rather than by quiz
+func getServedQuestions(ctx context.Context, session models.QuizSession) ([]models.QuizQuestion, error) {
+	questions, err := db.Queries.GetQuestionsByIds(ctx, session.QuestionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.QuizQuestion, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	served := make([]models.QuizQuestion, 0, len(session.QuestionIds))
+	for _, questionID := range session.QuestionIds {
+		if question, ok := byID[questionID]; ok {
+			served = append(served, question)
+		}
+	}
+
+	return served, nil
+}
+
+func readSessionState(session models.QuizSession) (optionOrders, map[uuid.UUID]givenAnswer, error) {
+	orders := optionOrders{}
+	if err := json.Unmarshal(session.OptionOrders, &orders); err != nil {
+		return nil, nil, err
+	}
+
+	drafts := map[uuid.UUID]givenAnswer{}
+	if err := json.Unmarshal(session.DraftAnswers, &drafts); err != nil {
+		return nil, nil, err
+	}
+
+	return orders, drafts, nil
+}
+
+// isSessionExpired allows the submit grace past the deadline so answers sent
+// right before it are not lost to latency
+func isSessionExpired(session models.QuizSession, now time.Time) bool {
+	return session.DeadlineAt.Valid &&
+		now.After(session.DeadlineAt.Time.Add(config.GetConfig().QuizSubmitGrace))
+}
+
+func serializeQuizSession(
+	quiz models.Quiz,
+	session models.QuizSession,
+	questions []models.QuizQuestion,
+	orders optionOrders,
+	drafts map[uuid.UUID]givenAnswer,
+) dto.QuizDTO {
+	servedQuestions := make([]dto.QuizQuestionDTO, len(questions))
+	answers := make([]dto.VerifyQuizQuestion, 0, len(drafts))
+
+	for i, question := range questions {
+		options := []dto.QuizOptionDTO{}
+		for j := range question.Options {
+			original := orders.toOriginal(question.ID, j+1)
+			options = append(options, dto.QuizOptionDTO{
+				Option:       question.Options[original-1],
+				OptionNumber: j + 1,
+			})
+		}
+
+		servedQuestions[i] = dto.QuizQuestionDTO{
+			QuestionID:     question.ID,
+			QuestionNumber: i + 1,
+			Type:           string(question.QuestionType),
+			Question:       question.Question.String,
+			Options:        options,
+		}
+
+		if given, ok := drafts[question.ID]; ok {
+			answers = append(answers, servedAnswer(question, given, orders))
+		}
+	}
+
+	return dto.QuizDTO{
+		QuizID:           quiz.ID,
+		SessionID:        session.ID,
+		TopicName:        quiz.Name.String,
+		StartedAt:        session.StartedAt,
+		DeadlineAt:       nullTimePtr(session.DeadlineAt),
+		TimeLimitSeconds: int(quiz.TimeLimitSeconds.Int32),
+		Questions:        servedQuestions,
+		Answers:          answers,
+	}
+}
+
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
@@ -0,0 +1,202 @@
+package quizservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easc01/mindo-server/internal/middleware"
+	"github.com/easc01/mindo-server/internal/models"
+	topicrepository "github.com/easc01/mindo-server/internal/repository/topic_repository"
+	aiservice "github.com/easc01/mindo-server/internal/services/ai_service"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/message"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// examQuestionsPerTopic is how many questions of each topic bank an exam draws
+const examQuestionsPerTopic = 3
+
+// topicQuizDrafts keeps learners opening the same topic from generating its
+// quiz twice
+var topicQuizDrafts util.Coalescer[models.Quiz]
+
+// GetTopicQuiz opens a session on the quiz of a topic, topics without a quiz
+// get one generated on first use
+func GetTopicQuiz(c *gin.Context, topicID uuid.UUID) (dto.QuizDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	quiz, err := db.Queries.GetQuizByTopicId(c, util.GetNullUUID(topicID))
+	if errors.Is(err, sql.ErrNoRows) {
+		quiz, err, _ = topicQuizDrafts.Do(topicID.String(), func() (models.Quiz, error) {
+			return generateTopicQuiz(context.Background(), topicID)
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizDTO{}, http.StatusNotFound, fmt.Errorf("topic of id %s not found", topicID)
+		}
+	}
+	if err != nil {
+		logger.Log.Errorf("failed to get quiz of topic %s, %s", topicID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	return startQuiz(c, user.AppUser.UserID, quiz)
+}
+
+// StartQuiz opens a new session on a quiz, starting a quiz played before is a
+// retake. Exams draw a fresh set of questions
+func StartQuiz(c *gin.Context, quizID uuid.UUID) (dto.QuizDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	quiz, err := db.Queries.GetQuizById(c, quizID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizDTO{}, http.StatusNotFound, fmt.Errorf("quiz of id %s not found", quizID)
+		}
+		logger.Log.Errorf("failed to get quiz %s, %s", quizID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	if quiz.PlaylistID.Valid {
+		return startPlaylistExam(c, user.AppUser.UserID, quiz.PlaylistID.UUID)
+	}
+
+	return startQuiz(c, user.AppUser.UserID, quiz)
+}
+
+// GetPlaylistExam opens an exam session with questions drawn from the quiz of
+// every topic in the playlist
+func GetPlaylistExam(c *gin.Context, playlistID uuid.UUID) (dto.QuizDTO, int, error) {
+	user, ok := middleware.GetUser(c)
+	if !ok || user.AppUser == nil {
+		return dto.QuizDTO{}, http.StatusUnauthorized, fmt.Errorf(message.NullAppUserContext)
+	}
+
+	return startPlaylistExam(c, user.AppUser.UserID, playlistID)
+}
+
+func startQuiz(c *gin.Context, userID uuid.UUID, quiz models.Quiz) (dto.QuizDTO, int, error) {
+	questions, err := db.Queries.GetQuestionsByQuizId(c, quiz.ID)
+	if err != nil {
+		logger.Log.Errorf("failed to get questions of quiz %s, %s", quiz.ID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+	if len(questions) == 0 {
+		return dto.QuizDTO{}, http.StatusUnprocessableEntity, fmt.Errorf("quiz %s has no questions", quiz.ID)
+	}
+
+	served, err := openQuizSession(c, userID, quiz, questions)
+	if err != nil {
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	return served, http.StatusAccepted, nil
+}
+
+// startPlaylistExam only creates the exam once some topic has a quiz, so a
+// playlist never has an exam nobody can take
+func startPlaylistExam(c *gin.Context, userID uuid.UUID, playlistID uuid.UUID) (dto.QuizDTO, int, error) {
+	questions, err := db.Queries.GetPlaylistExamQuestions(c, models.GetPlaylistExamQuestionsParams{
+		PlaylistID: playlistID,
+		PerTopic:   examQuestionsPerTopic,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to draw exam questions of playlist %s, %s", playlistID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+	if len(questions) == 0 {
+		return dto.QuizDTO{}, http.StatusUnprocessableEntity, fmt.Errorf(
+			"playlist %s has no topic quizzes to draw an exam from",
+			playlistID,
+		)
+	}
+
+	exam, err := db.Queries.UpsertPlaylistExam(c, playlistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.QuizDTO{}, http.StatusNotFound, fmt.Errorf("playlist of id %s not found", playlistID)
+		}
+		logger.Log.Errorf("failed to get exam of playlist %s, %s", playlistID, err.Error())
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	served, err := openQuizSession(c, userID, exam, questions)
+	if err != nil {
+		return dto.QuizDTO{}, http.StatusInternalServerError, err
+	}
+
+	return served, http.StatusAccepted, nil
+}
+
+// generateTopicQuiz builds the question bank of a topic, when another instance
+// saved one while the generator ran that quiz is returned instead
+func generateTopicQuiz(ctx context.Context, topicID uuid.UUID) (models.Quiz, error) {
+	topic, err := topicrepository.GetTopicByIDWithVideos(ctx, topicID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Errorf("failed to get topic %s for quiz, %s", topicID, err.Error())
+		}
+		return models.Quiz{}, err
+	}
+
+	sources, err := getQuestionSources(ctx, topic)
+	if err != nil {
+		logger.Log.Errorf("failed to get study material of topic %s for quiz, %s", topicID, err.Error())
+		return models.Quiz{}, err
+	}
+
+	generatedQuiz, err := aiservice.GenerateQuiz(sources.withSources(dto.GenerateQuizParams{
+		TopicName:     topic.Name.String,
+		QuestionCount: QuizQuestionCount,
+		QuestionTypes: QuestionTypes,
+	}))
+	if err != nil {
+		logger.Log.Errorf("failed to generate quiz of topic %s, %s", topicID, err.Error())
+		return models.Quiz{}, err
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Quiz{}, err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	quiz, err := qtx.InsertTopicQuiz(ctx, models.InsertTopicQuizParams{
+		Name:    topic.Name,
+		TopicID: util.GetNullUUID(topicID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return db.Queries.GetQuizByTopicId(ctx, util.GetNullUUID(topicID))
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to save quiz of topic %s, %s", topicID, err.Error())
+		return models.Quiz{}, err
+	}
+
+	if _, err := saveGeneratedQuestions(ctx, qtx, quiz.ID, generatedQuiz, sources); err != nil {
+		tx.Rollback()
+		logger.Log.Errorf("failed to save questions of quiz %s, %s", quiz.ID, err.Error())
+		return models.Quiz{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Quiz{}, err
+	}
+
+	logger.Log.Infof("generated quiz for topic %s", topicID)
+	return quiz, nil
+}
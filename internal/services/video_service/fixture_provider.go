@@ -0,0 +1,133 @@
+package videoservice
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+//go:embed fixtures/videos.json
+var defaultFixtures []byte
+
+type videoFixture struct {
+	Keywords []string           `json:"keywords"`
+	Videos   []dto.VideoMiniDTO `json:"videos"`
+}
+
+// fixtureProvider answers searches from a fixture file without any network
+// access, the same query always yields the same videos
+type fixtureProvider struct {
+	fixtures []videoFixture
+}
+
+// newFixtureProvider loads fixtures from path, or the embedded set when path is blank
+func newFixtureProvider(path string) (*fixtureProvider, error) {
+	content := defaultFixtures
+	if path != constant.Blank {
+		fileContent, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read video fixtures %s: %w", path, err)
+		}
+		content = fileContent
+	}
+
+	var fixtures []videoFixture
+	if err := json.Unmarshal(content, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse video fixtures: %w", err)
+	}
+
+	for i := range fixtures {
+		for j := range fixtures[i].Keywords {
+			fixtures[i].Keywords[j] = strings.ToLower(fixtures[i].Keywords[j])
+		}
+		for j := range fixtures[i].Videos {
+			fixtures[i].Videos[j].Provider = constant.VideoProviderFixture
+		}
+	}
+
+	return &fixtureProvider{fixtures: fixtures}, nil
+}
+
+func (p *fixtureProvider) Name() string {
+	return constant.VideoProviderFixture
+}
+
+// SearchVideos returns the videos of fixtures sharing the most keywords with the
+// query, and generated placeholder videos when no fixture matches
+func (p *fixtureProvider) SearchVideos(
+	ctx context.Context,
+	query string,
+	maxResults int,
+) ([]dto.VideoMiniDTO, error) {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	type match struct {
+		index int
+		hits  int
+	}
+	var matches []match
+	for i, fixture := range p.fixtures {
+		hits := 0
+		for _, keyword := range fixture.Keywords {
+			for _, word := range words {
+				if word == keyword {
+					hits++
+				}
+			}
+		}
+		if hits > 0 {
+			matches = append(matches, match{index: i, hits: hits})
+		}
+	}
+
+	// stable keeps fixture file order between equally good matches
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].hits > matches[j].hits
+	})
+
+	videos := []dto.VideoMiniDTO{}
+	for _, m := range matches {
+		for _, video := range p.fixtures[m.index].Videos {
+			if len(videos) == maxResults {
+				return videos, nil
+			}
+			videos = append(videos, video)
+		}
+	}
+
+	if len(videos) == 0 {
+		return generatedVideos(query, maxResults), nil
+	}
+	return videos, nil
+}
+
+func generatedVideos(query string, count int) []dto.VideoMiniDTO {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(query))))
+	seed := hex.EncodeToString(sum[:])[:12]
+	publishedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	videos := make([]dto.VideoMiniDTO, count)
+	for i := range videos {
+		videos[i] = dto.VideoMiniDTO{
+			VideoID:      fmt.Sprintf("fixture-%s-%d", seed, i+1),
+			Provider:     constant.VideoProviderFixture,
+			Title:        fmt.Sprintf("%s, part %d", query, i+1),
+			VideoDate:    publishedAt.AddDate(0, 0, i),
+			ChannelTitle: "Mindo Fixtures",
+		}
+	}
+	return videos
+}
@@ -0,0 +1,102 @@
+package videoservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+// peerTubeProvider searches a single PeerTube instance through its public search api
+type peerTubeProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newPeerTubeProvider(baseURL string) *peerTubeProvider {
+	return &peerTubeProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *peerTubeProvider) Name() string {
+	return constant.VideoProviderPeerTube
+}
+
+func (p *peerTubeProvider) SearchVideos(
+	ctx context.Context,
+	query string,
+	maxResults int,
+) ([]dto.VideoMiniDTO, error) {
+	searchURL := fmt.Sprintf(
+		"%s/api/v1/search/videos?search=%s&count=%s&sort=-match&nsfw=false",
+		p.baseURL,
+		url.QueryEscape(query),
+		strconv.Itoa(maxResults),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+
+	logger.Log.Infof("peertube api response statusCode, %d, %s", res.StatusCode, query)
+
+	if res.StatusCode != http.StatusOK {
+		return []dto.VideoMiniDTO{}, fmt.Errorf(
+			"peertube api returned status code %d: %s",
+			res.StatusCode,
+			string(body[:min(len(body), 100)]),
+		)
+	}
+
+	var responseJson dto.PeerTubeSearchResponse
+	if err := json.Unmarshal(body, &responseJson); err != nil {
+		return []dto.VideoMiniDTO{}, fmt.Errorf("failed to parse PeerTube response: %w", err)
+	}
+
+	videos := make([]dto.VideoMiniDTO, 0, len(responseJson.Data))
+	for _, video := range responseJson.Data {
+		channelTitle := video.Channel.DisplayName
+		if channelTitle == constant.Blank {
+			channelTitle = video.Channel.Name
+		}
+
+		videos = append(videos, dto.VideoMiniDTO{
+			VideoID:      video.UUID,
+			Provider:     constant.VideoProviderPeerTube,
+			Title:        video.Name,
+			VideoDate:    video.PublishedAt,
+			ChannelTitle: channelTitle,
+			ChannelID:    video.Channel.Name,
+			ThumbnailURL: p.baseURL + video.ThumbnailPath,
+
+			DurationSeconds: video.Duration,
+			ViewCount:       video.Views,
+			LikeCount:       video.Likes,
+		})
+	}
+
+	return videos, nil
+}
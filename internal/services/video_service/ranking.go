@@ -0,0 +1,98 @@
+package videoservice
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/pkg/dto"
+)
+
+// RankWeights scales each signal of the ranking function, every signal is
+// normalised to 0..1 before weighting
+type RankWeights struct {
+	IdealDuration time.Duration
+	Views         float64
+	Likes         float64
+	Captions      float64
+	HD            float64
+	Duration      float64
+	Recency       float64
+}
+
+// Views beyond this count add nothing to the popularity signal
+const saturatedViews = 10_000_000
+
+// A like ratio of 4% or more earns the full approval signal
+const saturatedLikeRatio = 0.04
+
+func RankWeightsFromConfig(cfg *config.Config) RankWeights {
+	return RankWeights{
+		IdealDuration: cfg.VideoIdealDuration,
+		Views:         cfg.VideoRankViewsWeight,
+		Likes:         cfg.VideoRankLikesWeight,
+		Captions:      cfg.VideoRankCaptionsWeight,
+		HD:            cfg.VideoRankHDWeight,
+		Duration:      cfg.VideoRankDurationWeight,
+		Recency:       cfg.VideoRankRecencyWeight,
+	}
+}
+
+// ScoreVideo rates a video, details a provider did not return count as zero
+func ScoreVideo(video dto.VideoMiniDTO, weights RankWeights, now time.Time) float64 {
+	popularity := math.Min(math.Log10(1+float64(video.ViewCount))/math.Log10(saturatedViews), 1)
+
+	approval := 0.0
+	if video.ViewCount > 0 {
+		approval = math.Min(float64(video.LikeCount)/float64(video.ViewCount)/saturatedLikeRatio, 1)
+	}
+
+	captions := 0.0
+	if video.HasCaptions {
+		captions = 1
+	}
+
+	hd := 0.0
+	if video.Definition == "hd" {
+		hd = 1
+	}
+
+	// 1 at the ideal length, falling linearly to 0 at none or double of it
+	durationFit := 0.0
+	if video.DurationSeconds > 0 && weights.IdealDuration > 0 {
+		ideal := weights.IdealDuration.Seconds()
+		durationFit = math.Max(1-math.Abs(float64(video.DurationSeconds)-ideal)/ideal, 0)
+	}
+
+	// hyperbolic decay, 1 when new, a half at two years and a third at four
+	recency := 0.0
+	if !video.VideoDate.IsZero() {
+		ageYears := math.Max(now.Sub(video.VideoDate).Hours()/24/365, 0)
+		recency = 1 / (1 + ageYears/2)
+	}
+
+	return weights.Views*popularity +
+		weights.Likes*approval +
+		weights.Captions*captions +
+		weights.HD*hd +
+		weights.Duration*durationFit +
+		weights.Recency*recency
+}
+
+// RankVideos scores videos with the configured weights and sorts them best
+// first, equal scores keep the provider's order
+func RankVideos(videos []dto.VideoMiniDTO) []dto.VideoMiniDTO {
+	weights := RankWeightsFromConfig(config.GetConfig())
+	now := time.Now()
+
+	for i := range videos {
+		videos[i].RankScore = ScoreVideo(videos[i], weights, now)
+	}
+
+	sort.SliceStable(videos, func(i, j int) bool {
+		return videos[i].RankScore > videos[j].RankScore
+	})
+
+	return videos
+}
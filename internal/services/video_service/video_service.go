@@ -0,0 +1,53 @@
+package videoservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+// VideoProvider searches a video source for topic videos
+type VideoProvider interface {
+	// Name is the provider stored alongside every video it returns
+	Name() string
+	SearchVideos(ctx context.Context, query string, maxResults int) ([]dto.VideoMiniDTO, error)
+}
+
+var (
+	provider     VideoProvider
+	providerOnce sync.Once
+)
+
+// GetProvider returns the provider selected by VIDEO_PROVIDER, built once
+func GetProvider() VideoProvider {
+	providerOnce.Do(func() {
+		var err error
+		provider, err = NewProvider(config.GetConfig())
+		if err != nil {
+			logger.Log.Errorf("%s, falling back to %s", err.Error(), constant.VideoProviderYoutube)
+			provider = &youtubeProvider{}
+		}
+		logger.Log.Infof("using %s video provider", provider.Name())
+	})
+
+	return provider
+}
+
+// NewProvider builds the video provider named in the config
+func NewProvider(cfg *config.Config) (VideoProvider, error) {
+	switch cfg.VideoProvider {
+	case constant.VideoProviderYoutube:
+		return &youtubeProvider{}, nil
+	case constant.VideoProviderPeerTube:
+		return newPeerTubeProvider(cfg.PeerTubeURL), nil
+	case constant.VideoProviderFixture:
+		return newFixtureProvider(cfg.VideoFixturePath)
+	default:
+		return nil, fmt.Errorf("unknown video provider %s", cfg.VideoProvider)
+	}
+}
@@ -0,0 +1,23 @@
+package videoservice
+
+import (
+	"context"
+
+	youtubeservice "github.com/easc01/mindo-server/internal/services/youtube_service"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+type youtubeProvider struct{}
+
+func (p *youtubeProvider) Name() string {
+	return constant.VideoProviderYoutube
+}
+
+func (p *youtubeProvider) SearchVideos(
+	ctx context.Context,
+	query string,
+	maxResults int,
+) ([]dto.VideoMiniDTO, error) {
+	return youtubeservice.SearchVideosByTopic(ctx, query, maxResults)
+}
@@ -0,0 +1,166 @@
+package youtubeservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/easc01/mindo-server/internal/config"
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/db"
+	"github.com/easc01/mindo-server/pkg/dto"
+	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Quota cost of every youtube data api endpoint we call
+const (
+	EndpointSearch = "search.list"
+	SearchCost     = 100
+	EndpointVideos = "videos.list"
+	VideosCost     = 1
+)
+
+var ErrQuotaExhausted = errors.New("youtube quota exhausted for today")
+
+// youtube resets the daily quota at midnight pacific time
+var quotaLocation = mustLoadLocation("America/Los_Angeles")
+
+// exhaustedDay is the quota day youtube itself rejected a call on, checked
+// before the ledger so an out of sync ledger does not keep hitting the api
+var exhaustedDay = struct {
+	sync.Mutex
+	day time.Time
+}{}
+
+type backgroundPriorityKey struct{}
+
+// WithBackgroundPriority marks calls made with ctx as background work, which
+// may not spend the reserve kept for learners
+func WithBackgroundPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundPriorityKey{}, true)
+}
+
+func isBackground(ctx context.Context) bool {
+	background, _ := ctx.Value(backgroundPriorityKey{}).(bool)
+	return background
+}
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// quotaDay returns the youtube quota day of t as a UTC midnight date
+func quotaDay(t time.Time) time.Time {
+	year, month, day := t.In(quotaLocation).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ReserveQuota books units for a call to endpoint on today's ledger, it returns
+// ErrQuotaExhausted without booking when the budget cannot cover them
+func ReserveQuota(ctx context.Context, endpoint string, units int) error {
+	cfg := config.GetConfig()
+	day := quotaDay(time.Now())
+
+	exhaustedDay.Lock()
+	exhausted := exhaustedDay.day.Equal(day)
+	exhaustedDay.Unlock()
+	if exhausted {
+		return ErrQuotaExhausted
+	}
+
+	limit := cfg.YoutubeDailyQuota
+	if isBackground(ctx) {
+		limit -= cfg.YoutubeQuotaReserve
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := db.Queries.WithTx(tx)
+
+	if err := qtx.LockYoutubeQuota(ctx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	used, err := qtx.GetYoutubeQuotaUnitsByDate(ctx, day)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if int(used)+units > limit {
+		tx.Rollback()
+		logger.Log.Warnf("youtube quota budget reached, %d of %d units used", used, limit)
+		return ErrQuotaExhausted
+	}
+
+	if err := qtx.AddYoutubeQuotaUsage(ctx, models.AddYoutubeQuotaUsageParams{
+		UsageDate: day,
+		Endpoint:  endpoint,
+		Units:     int32(units),
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// markQuotaExhausted stops further calls until the next quota day
+func markQuotaExhausted() {
+	exhaustedDay.Lock()
+	exhaustedDay.day = quotaDay(time.Now())
+	exhaustedDay.Unlock()
+
+	logger.Log.Errorf("youtube rejected a call for quota, pausing calls until the next quota day")
+}
+
+// GetQuotaUsage returns today's budget along with the ledger of the last days
+func GetQuotaUsage(c *gin.Context, days int) (dto.YoutubeQuotaDTO, int, error) {
+	cfg := config.GetConfig()
+	day := quotaDay(time.Now())
+
+	usage, err := db.Queries.GetYoutubeQuotaUsageSince(c, day.AddDate(0, 0, -(days-1)))
+	if err != nil {
+		logger.Log.Errorf("failed to get youtube quota usage, %s", err.Error())
+		return dto.YoutubeQuotaDTO{}, http.StatusInternalServerError, err
+	}
+
+	used := 0
+	history := make([]dto.YoutubeQuotaUsageDTO, len(usage))
+	for i, row := range usage {
+		if row.UsageDate.Equal(day) {
+			used += int(row.Units)
+		}
+		history[i] = dto.YoutubeQuotaUsageDTO{
+			Date:     row.UsageDate,
+			Endpoint: row.Endpoint,
+			Units:    int(row.Units),
+			Calls:    int(row.Calls),
+		}
+	}
+
+	exhaustedDay.Lock()
+	exhausted := exhaustedDay.day.Equal(day)
+	exhaustedDay.Unlock()
+
+	return dto.YoutubeQuotaDTO{
+		Date:       day,
+		DailyLimit: cfg.YoutubeDailyQuota,
+		Reserve:    cfg.YoutubeQuotaReserve,
+		Used:       used,
+		Remaining:  max(cfg.YoutubeDailyQuota-used, 0),
+		Exhausted:  exhausted || used >= cfg.YoutubeDailyQuota,
+		History:    history,
+	}, http.StatusAccepted, nil
+}
@@ -0,0 +1,56 @@
+package youtubeservice
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/easc01/mindo-server/pkg/utils/constant"
+)
+
+var (
+	ErrVideoNotFound   = errors.New("youtube video not found")
+	ErrInvalidVideoURL = errors.New("not a youtube video url or id")
+)
+
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// ParseVideoID extracts the video id from the usual youtube url shapes, watch,
+// youtu.be, embed, shorts and live links, or accepts a bare id
+func ParseVideoID(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if videoIDPattern.MatchString(raw) {
+		return raw, nil
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return constant.Blank, ErrInvalidVideoURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+
+	var videoID string
+	switch host {
+	case "youtu.be":
+		videoID = segments[0]
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		if segments[0] == "watch" {
+			videoID = parsed.Query().Get("v")
+		} else if len(segments) == 2 &&
+			(segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live" || segments[0] == "v") {
+			videoID = segments[1]
+		}
+	}
+
+	if !videoIDPattern.MatchString(videoID) {
+		return constant.Blank, ErrInvalidVideoURL
+	}
+	return videoID, nil
+}
@@ -1,19 +1,23 @@
 package youtubeservice
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/easc01/mindo-server/internal/config"
 	"github.com/easc01/mindo-server/pkg/dto"
 	"github.com/easc01/mindo-server/pkg/logger"
+	"github.com/easc01/mindo-server/pkg/utils/constant"
 )
 
-func SearchVideosByTopic(query string, maxResults int) ([]dto.VideoMiniDTO, error) {
+func SearchVideosByTopic(ctx context.Context, query string, maxResults int) ([]dto.VideoMiniDTO, error) {
 	url := fmt.Sprintf(
 		"https://www.googleapis.com/youtube/v3/search?key=%s&q=%s&safeSearch=strict&type=video&videoEmbeddable=true&part=snippet&videoDuration=medium&maxResults=%s",
 		config.GetConfig().YoutubeAPIKey,
@@ -21,46 +25,206 @@ func SearchVideosByTopic(query string, maxResults int) ([]dto.VideoMiniDTO, erro
 		strconv.Itoa(maxResults),
 	)
 
+	if err := ReserveQuota(ctx, EndpointSearch, SearchCost); err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+
+	var responseJson dto.YouTubeSearchResponse
+	if err := getYoutube(ctx, url, query, &responseJson); err != nil {
+		return []dto.VideoMiniDTO{}, err
+	}
+
+	videos := serializeYoutubeResponse(responseJson)
+
+	// The search is already paid for, videos without details still beat no videos
+	if err := enrichVideos(ctx, videos); err != nil {
+		logger.Log.Warnf("failed to get youtube video details of %s, %s", query, err.Error())
+	}
+
+	return videos, nil
+}
+
+// enrichVideos fills duration, statistics, captions and definition of videos
+// in place with a single videos.list call
+func enrichVideos(ctx context.Context, videos []dto.VideoMiniDTO) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(videos))
+	for i, video := range videos {
+		ids[i] = video.VideoID
+	}
+
+	items, err := listVideos(ctx, ids, "contentDetails,statistics")
+	if err != nil {
+		return err
+	}
+
+	details := make(map[string]dto.YouTubeVideoListItem, len(items))
+	for _, item := range items {
+		details[item.ID] = item
+	}
+
+	for i := range videos {
+		if item, ok := details[videos[i].VideoID]; ok {
+			applyVideoDetails(&videos[i], item)
+		}
+	}
+
+	return nil
+}
+
+// GetVideoById looks up a single video with its details, it returns
+// ErrVideoNotFound when youtube has no such video
+func GetVideoById(ctx context.Context, videoID string) (dto.VideoMiniDTO, error) {
+	items, err := listVideos(ctx, []string{videoID}, "snippet,contentDetails,statistics")
+	if err != nil {
+		return dto.VideoMiniDTO{}, err
+	}
+
+	if len(items) == 0 {
+		return dto.VideoMiniDTO{}, ErrVideoNotFound
+	}
+
+	video := dto.VideoMiniDTO{
+		VideoID:      items[0].ID,
+		Provider:     constant.VideoProviderYoutube,
+		Title:        items[0].Snippet.Title,
+		VideoDate:    items[0].Snippet.PublishedAt,
+		ChannelTitle: items[0].Snippet.ChannelTitle,
+		ChannelID:    items[0].Snippet.ChannelID,
+		ThumbnailURL: bestThumbnail(items[0].Snippet.Thumbnails),
+	}
+	applyVideoDetails(&video, items[0])
+
+	return video, nil
+}
+
+// listVideos calls videos.list for up to 50 ids with the given parts
+func listVideos(ctx context.Context, ids []string, parts string) ([]dto.YouTubeVideoListItem, error) {
+	url := fmt.Sprintf(
+		"https://www.googleapis.com/youtube/v3/videos?key=%s&id=%s&part=%s&maxResults=%s",
+		config.GetConfig().YoutubeAPIKey,
+		url.QueryEscape(strings.Join(ids, ",")),
+		parts,
+		strconv.Itoa(len(ids)),
+	)
+
+	if err := ReserveQuota(ctx, EndpointVideos, VideosCost); err != nil {
+		return []dto.YouTubeVideoListItem{}, err
+	}
+
+	var responseJson dto.YouTubeVideosResponse
+	if err := getYoutube(ctx, url, "video details", &responseJson); err != nil {
+		return []dto.YouTubeVideoListItem{}, err
+	}
+
+	return responseJson.Items, nil
+}
+
+func applyVideoDetails(video *dto.VideoMiniDTO, item dto.YouTubeVideoListItem) {
+	video.DurationSeconds = parseISODuration(item.ContentDetails.Duration)
+	video.Definition = item.ContentDetails.Definition
+	video.HasCaptions = item.ContentDetails.Caption == "true"
+	video.ViewCount, _ = strconv.ParseInt(item.Statistics.ViewCount, 10, 64)
+	video.LikeCount, _ = strconv.ParseInt(item.Statistics.LikeCount, 10, 64)
+}
+
+// getYoutube calls a youtube data api url and decodes a 200 response into out,
+// a quota rejection pauses further calls and returns ErrQuotaExhausted
+func getYoutube(ctx context.Context, url string, label string, out any) error {
 	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 
 	if err != nil {
-		return []dto.VideoMiniDTO{}, err
+		return err
 	}
 
 	res, err := client.Do(req)
 	if err != nil {
-		return []dto.VideoMiniDTO{}, err
+		return err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return []dto.VideoMiniDTO{}, err
+		return err
 	}
 
-	logger.Log.Infof("youTube api response statusCode, %d, %s", res.StatusCode, query)
+	logger.Log.Infof("youTube api response statusCode, %d, %s", res.StatusCode, label)
+
+	if res.StatusCode == http.StatusForbidden && isQuotaError(body) {
+		markQuotaExhausted()
+		return ErrQuotaExhausted
+	}
 
 	// Only try to parse if we got a 200 OK
 	if res.StatusCode != http.StatusOK {
-		return []dto.VideoMiniDTO{}, fmt.Errorf(
+		return fmt.Errorf(
 			"youtube api returned status code %d: %s",
 			res.StatusCode,
-			string(body[:100]),
+			string(body[:min(len(body), 100)]),
 		)
 	}
 
-	var responseJson dto.YouTubeSearchResponse
-	err = json.Unmarshal(body, &responseJson)
-	if err != nil {
-		return []dto.VideoMiniDTO{}, fmt.Errorf(
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf(
 			"failed to parse YouTube response: %w, body: %s",
 			err,
-			string(body[:200]),
+			string(body[:min(len(body), 200)]),
 		)
 	}
 
-	return serializeYoutubeResponse(responseJson), nil
+	return nil
+}
+
+// isQuotaError reports whether a youtube error body carries a quota reason
+func isQuotaError(body []byte) bool {
+	var responseJson dto.YouTubeErrorResponse
+	if err := json.Unmarshal(body, &responseJson); err != nil {
+		return false
+	}
+
+	for _, e := range responseJson.Error.Errors {
+		if e.Reason == "quotaExceeded" || e.Reason == "dailyLimitExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration converts youtube's ISO 8601 durations like PT1H2M3S to
+// seconds, anything it cannot read counts as unknown
+func parseISODuration(duration string) int {
+	match := isoDurationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return 0
+	}
+
+	seconds := 0
+	for i, unit := range []int{24 * 60 * 60, 60 * 60, 60, 1} {
+		if value, err := strconv.Atoi(match[i+1]); err == nil {
+			seconds += value * unit
+		}
+	}
+	return seconds
+}
+
+// bestThumbnail returns the largest thumbnail youtube sent
+func bestThumbnail(thumbnails dto.YouTubeThumbnails) string {
+	for _, thumbnail := range []dto.YouTubeThumbnail{
+		thumbnails.High,
+		thumbnails.Medium,
+		thumbnails.Default,
+	} {
+		if thumbnail.URL != constant.Blank {
+			return thumbnail.URL
+		}
+	}
+	return constant.Blank
 }
 
 func serializeYoutubeResponse(response dto.YouTubeSearchResponse) []dto.VideoMiniDTO {
@@ -69,10 +233,12 @@ func serializeYoutubeResponse(response dto.YouTubeSearchResponse) []dto.VideoMin
 	for _, video := range response.Items {
 		serializedVideos = append(serializedVideos, dto.VideoMiniDTO{
 			VideoID:      video.ID.VideoID,
+			Provider:     constant.VideoProviderYoutube,
 			Title:        video.Snippet.Title,
 			VideoDate:    video.Snippet.PublishedAt,
 			ChannelTitle: video.Snippet.ChannelTitle,
-			ThumbnailURL: video.Snippet.Thumbnails.Default.URL,
+			ChannelID:    video.Snippet.ChannelID,
+			ThumbnailURL: bestThumbnail(video.Snippet.Thumbnails),
 		})
 	}
 
@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/easc01/mindo-server/internal/config"
+	playlistservice "github.com/easc01/mindo-server/internal/services/playlist_service"
+	quizservice "github.com/easc01/mindo-server/internal/services/quiz_service"
+	"github.com/easc01/mindo-server/pkg/logger"
+)
+
+// StartWorkers launches every periodic background job on its own goroutine
+func StartWorkers() {
+	cfg := config.GetConfig()
+
+	runEvery("playlist view stats", cfg.PlaylistStatsInterval, playlistservice.RefreshPlaylistViewStats)
+	runEvery("similar playlists", cfg.SimilarPlaylistsInterval, playlistservice.RefreshPlaylistSimilarities)
+	runEvery("expired video refresh", cfg.VideoRefreshInterval, playlistservice.RefreshExpiredVideos)
+	runEvery("video progress flush", cfg.VideoProgressFlush, playlistservice.FlushVideoProgress)
+	runEvery("expired quiz sessions", cfg.QuizSessionSweep, quizservice.FinalizeExpiredQuizSessions)
+	runEvery("playlist leaderboards", cfg.LeaderboardInterval, quizservice.RefreshPlaylistLeaderboards)
+}
+
+// runEvery runs the job immediately and then once per interval, a failing or
+// panicking run is logged and does not stop the next one
+func runEvery(name string, interval time.Duration, job func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runJob(name, interval, job)
+			<-ticker.C
+		}
+	}()
+
+	logger.Log.Infof("worker %s scheduled every %s", name, interval)
+}
+
+func runJob(name string, timeout time.Duration, job func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Errorf("worker %s panicked, %v", name, r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := job(ctx); err != nil {
+		logger.Log.Errorf("worker %s failed, %s", name, err.Error())
+		return
+	}
+	logger.Log.Debugf("worker %s finished in %s", name, time.Since(start))
+}
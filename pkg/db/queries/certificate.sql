@@ -0,0 +1,41 @@
+-- name: CreateCertificate :one
+-- returns the existing certificate when the user already has one for the playlist
+INSERT INTO certificate (
+    code,
+    user_id,
+    playlist_id,
+    learner_name,
+    playlist_name,
+    issued_at,
+    signature,
+    updated_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (user_id, playlist_id) DO UPDATE
+SET
+    updated_at = certificate.updated_at
+RETURNING *;
+
+-- name: GetCertificateByCode :one
+SELECT *
+FROM certificate
+WHERE code = $1;
+
+-- name: GetCertificatesByUserId :many
+SELECT *
+FROM certificate
+WHERE user_id = $1
+ORDER BY issued_at DESC;
+
+-- name: GetCertificateLearner :one
+-- the names a certificate of the app user is issued under
+SELECT
+    au.name,
+    au.username
+FROM app_user au
+WHERE au.user_id = $1;
+
+-- name: GetPlaylistIdByTopicId :one
+SELECT playlist_id
+FROM topic
+WHERE id = $1;
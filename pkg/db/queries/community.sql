@@ -32,3 +32,9 @@ SELECT
   au.profile_picture_url
 FROM inserted_message im
 JOIN "app_user" au ON au.user_id = im.user_id;
+
+
+-- name: GetCommunityById :one
+SELECT *
+FROM community
+WHERE id = $1;
@@ -0,0 +1,218 @@
+-- name: UpsertTopicFlashcardDeck :one
+-- returns the deck of a topic, creating it on first use
+INSERT INTO flashcard_deck (
+    topic_id,
+    name,
+    updated_by
+)
+SELECT id, COALESCE(name, 'Topic'), $2
+FROM topic
+WHERE id = $1
+ON CONFLICT (topic_id) WHERE topic_id IS NOT NULL DO UPDATE
+SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: UpsertQuizFlashcardDeck :one
+-- returns the deck collecting missed questions of a quiz, creating it on first use
+INSERT INTO flashcard_deck (
+    quiz_id,
+    name
+)
+SELECT id, COALESCE(name, 'Quiz')
+FROM quiz
+WHERE id = $1
+ON CONFLICT (quiz_id) WHERE quiz_id IS NOT NULL DO UPDATE
+SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetFlashcardDeckByTopicId :one
+SELECT
+    fd.id,
+    fd.topic_id,
+    fd.name,
+    EXISTS (
+        SELECT 1
+        FROM user_flashcard_deck ufd
+        WHERE ufd.deck_id = fd.id
+        AND ufd.user_id = @user_id::uuid
+    ) AS is_studying
+FROM flashcard_deck fd
+WHERE fd.topic_id = @topic_id::uuid;
+
+-- name: GetFlashcardsByDeckId :many
+SELECT *
+FROM flashcard
+WHERE deck_id = $1
+ORDER BY created_at;
+
+-- name: CreateFlashcard :one
+INSERT INTO flashcard (
+    deck_id,
+    front,
+    back,
+    updated_by
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpsertQuestionFlashcard :one
+-- a question becomes a single card shared by every learner who missed it
+INSERT INTO flashcard (
+    deck_id,
+    front,
+    back,
+    quiz_question_id
+)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (quiz_question_id) DO UPDATE
+SET
+    -- cards edited by an admin keep their text, the rest follow the question
+    front = CASE WHEN flashcard.updated_by IS NULL THEN EXCLUDED.front ELSE flashcard.front END,
+    back = CASE WHEN flashcard.updated_by IS NULL THEN EXCLUDED.back ELSE flashcard.back END
+RETURNING *;
+
+-- name: UpdateFlashcard :one
+UPDATE flashcard
+SET
+    front = $2,
+    back = $3,
+    updated_at = NOW(),
+    updated_by = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUserFlashcardsByFlashcardId :exec
+DELETE FROM user_flashcard
+WHERE flashcard_id = $1;
+
+-- name: DeleteFlashcardById :execrows
+DELETE FROM flashcard
+WHERE id = $1;
+
+-- name: StudyFlashcardDeck :exec
+INSERT INTO user_flashcard_deck (
+    user_id,
+    deck_id,
+    updated_by
+)
+VALUES ($1, $2, $1)
+ON CONFLICT (user_id, deck_id) DO NOTHING;
+
+-- name: StopStudyingFlashcardDeck :execrows
+DELETE FROM user_flashcard_deck
+WHERE user_id = $1
+AND deck_id = $2;
+
+-- name: ScheduleMissedFlashcard :exec
+-- missing the question again counts as a lapse and makes the card due now
+INSERT INTO user_flashcard (
+    user_id,
+    flashcard_id,
+    updated_by
+)
+VALUES ($1, $2, $1)
+ON CONFLICT (user_id, flashcard_id) DO UPDATE
+SET
+    repetitions = 0,
+    interval_days = 0,
+    lapses = user_flashcard.lapses + 1,
+    due_at = LEAST(user_flashcard.due_at, NOW()),
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by;
+
+-- name: GetDueFlashcards :many
+-- cards the learner scheduled that are due, then never reviewed cards of studied decks
+SELECT
+    f.id,
+    f.deck_id,
+    fd.name AS deck_name,
+    fd.topic_id,
+    f.front,
+    f.back,
+    due.repetitions,
+    due.interval_days,
+    due.ease_factor,
+    due.due_at,
+    due.is_new
+FROM (
+    SELECT
+        uf.flashcard_id,
+        uf.repetitions,
+        uf.interval_days,
+        uf.ease_factor,
+        uf.due_at,
+        false AS is_new
+    FROM user_flashcard uf
+    WHERE uf.user_id = @user_id::uuid
+    AND uf.due_at <= NOW()
+    UNION ALL
+    SELECT
+        f.id,
+        0,
+        0,
+        2.5::double precision,
+        ufd.created_at,
+        true
+    FROM user_flashcard_deck ufd
+    JOIN flashcard f ON f.deck_id = ufd.deck_id
+    WHERE ufd.user_id = @user_id::uuid
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_flashcard uf
+        WHERE uf.flashcard_id = f.id
+        AND uf.user_id = @user_id::uuid
+    )
+) due
+JOIN flashcard f ON f.id = due.flashcard_id
+JOIN flashcard_deck fd ON fd.id = f.deck_id
+ORDER BY due.is_new, due.due_at, f.created_at
+LIMIT @row_limit::int;
+
+-- name: GetUserFlashcardForReview :one
+-- review state of a card the learner may review, new cards of studied decks get the defaults
+SELECT
+    f.id,
+    COALESCE(uf.ease_factor, 2.5)::double precision AS ease_factor,
+    COALESCE(uf.interval_days, 0)::int AS interval_days,
+    COALESCE(uf.repetitions, 0)::int AS repetitions,
+    COALESCE(uf.lapses, 0)::int AS lapses
+FROM flashcard f
+LEFT JOIN user_flashcard uf ON uf.flashcard_id = f.id
+AND uf.user_id = @user_id::uuid
+WHERE f.id = @flashcard_id::uuid
+AND (
+    uf.flashcard_id IS NOT NULL
+    OR EXISTS (
+        SELECT 1
+        FROM user_flashcard_deck ufd
+        WHERE ufd.deck_id = f.deck_id
+        AND ufd.user_id = @user_id::uuid
+    )
+);
+
+-- name: SaveFlashcardReview :one
+INSERT INTO user_flashcard (
+    user_id,
+    flashcard_id,
+    ease_factor,
+    interval_days,
+    repetitions,
+    lapses,
+    last_grade,
+    last_reviewed_at,
+    due_at,
+    updated_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), $8, $1)
+ON CONFLICT (user_id, flashcard_id) DO UPDATE
+SET
+    ease_factor = EXCLUDED.ease_factor,
+    interval_days = EXCLUDED.interval_days,
+    repetitions = EXCLUDED.repetitions,
+    lapses = EXCLUDED.lapses,
+    last_grade = EXCLUDED.last_grade,
+    last_reviewed_at = EXCLUDED.last_reviewed_at,
+    due_at = EXCLUDED.due_at,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by
+RETURNING *;
@@ -0,0 +1,63 @@
+-- name: CreateLearningPath :one
+INSERT INTO learning_path (
+    name,
+    description,
+    thumbnail_url,
+    updated_by
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CreateLearningPathStep :exec
+INSERT INTO learning_path_step (
+    learning_path_id,
+    playlist_id,
+    step_number,
+    updated_by
+)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteLearningPathById :execrows
+DELETE FROM learning_path
+WHERE id = $1;
+
+-- name: DeleteLearningPathSteps :exec
+DELETE FROM learning_path_step
+WHERE learning_path_id = $1;
+
+-- name: GetAllLearningPaths :many
+SELECT
+    lp.id,
+    lp.name,
+    lp.description,
+    lp.thumbnail_url,
+    lp.updated_at,
+    lp.created_at,
+    lp.updated_by,
+    (
+        SELECT COUNT(*)
+        FROM learning_path_step lps
+        WHERE lps.learning_path_id = lp.id
+    ) AS steps_count
+FROM learning_path lp
+ORDER BY lp.created_at DESC;
+
+-- name: GetLearningPathById :one
+SELECT *
+FROM learning_path
+WHERE id = $1;
+
+-- name: GetLearningPathSteps :many
+SELECT
+    lps.step_number,
+    p.id,
+    p.name,
+    p.description,
+    p.code,
+    p.thumbnail_url,
+    p.average_rating,
+    p.rating_count
+FROM learning_path_step lps
+JOIN playlist p ON p.id = lps.playlist_id
+WHERE lps.learning_path_id = $1
+ORDER BY lps.step_number;
@@ -21,8 +21,26 @@ VALUES (
     ) RETURNING *;
 
 
--- name: UpdatePlaylistViewCountById :exec
-UPDATE playlist SET views = views + $2 WHERE id = $1;
+-- name: GetPlaylistById :one
+SELECT * FROM playlist WHERE id = $1;
+
+-- name: RefreshPlaylistRatingById :exec
+UPDATE playlist
+SET
+    average_rating = COALESCE(
+        (
+            SELECT AVG(pr.rating)
+            FROM playlist_review pr
+            WHERE pr.playlist_id = $1
+        ),
+        0
+    ),
+    rating_count = (
+        SELECT COUNT(*)
+        FROM playlist_review pr
+        WHERE pr.playlist_id = $1
+    )
+WHERE id = $1;
 
 -- name: GetAllPlaylistsPreviews :many
 SELECT
@@ -33,6 +51,11 @@ SELECT
     p.thumbnail_url,
     p.interest_id,
     p.views,
+    p.average_rating,
+    p.rating_count,
+    COALESCE(s.views_24h, 0)::int AS views_24h,
+    COALESCE(s.views_7d, 0)::int AS views_7d,
+    COALESCE(s.trending_score, 0)::float8 AS trending_score,
     p.created_at,
     p.updated_at,
     p.updated_by,
@@ -40,6 +63,12 @@ SELECT
     COALESCE(COUNT(t.id), 0) AS topics_count
 FROM playlist p
 LEFT JOIN topic t ON t.playlist_id = p.id
-WHERE $1 = '' OR similarity(p.name, $1) > 0.05
-GROUP BY p.id
-ORDER BY similarity(p.name, $1) DESC;
+LEFT JOIN playlist_view_stat s ON s.playlist_id = p.id
+WHERE @search_tag::text = '' OR similarity(p.name, @search_tag::text) > 0.05
+GROUP BY p.id, s.playlist_id
+ORDER BY
+    CASE WHEN @sort_by::text = 'trending' THEN COALESCE(s.trending_score, 0) END DESC,
+    CASE WHEN @sort_by::text = 'rating' THEN p.average_rating END DESC,
+    CASE WHEN @sort_by::text = 'rating' THEN p.rating_count END DESC,
+    CASE WHEN @sort_by::text = 'ratingCount' THEN p.rating_count END DESC,
+    similarity(p.name, @search_tag::text) DESC;
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- name: CreatePlaylistPrerequisite :exec
+INSERT INTO playlist_prerequisite (
+    playlist_id,
+    prerequisite_playlist_id,
+    updated_by
+)
+VALUES ($1, $2, $3)
+ON CONFLICT (playlist_id, prerequisite_playlist_id) DO NOTHING;
+
+-- name: DeletePlaylistPrerequisite :execrows
+DELETE FROM playlist_prerequisite
+WHERE playlist_id = $1 AND prerequisite_playlist_id = $2;
+
+-- name: IsPlaylistPrerequisiteReachable :one
+WITH RECURSIVE reachable AS (
+    SELECT pp.prerequisite_playlist_id AS playlist_id
+    FROM playlist_prerequisite pp
+    WHERE pp.playlist_id = @from_playlist_id::uuid
+    UNION
+    SELECT pp.prerequisite_playlist_id
+    FROM playlist_prerequisite pp
+    JOIN reachable r ON r.playlist_id = pp.playlist_id
+)
+SELECT EXISTS (
+    SELECT 1
+    FROM reachable
+    WHERE playlist_id = @to_playlist_id::uuid
+);
+
+-- name: GetPlaylistPrerequisites :many
+SELECT
+    p.id,
+    p.name,
+    p.code,
+    p.thumbnail_url
+FROM playlist_prerequisite pp
+JOIN playlist p ON p.id = pp.prerequisite_playlist_id
+WHERE pp.playlist_id = $1
+ORDER BY pp.created_at;
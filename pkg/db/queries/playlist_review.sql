@@ -0,0 +1,30 @@
+-- name: UpsertPlaylistReview :one
+INSERT INTO
+    playlist_review (
+        playlist_id,
+        user_id,
+        rating,
+        review,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (playlist_id, user_id) DO UPDATE
+SET
+    rating = EXCLUDED.rating,
+    review = EXCLUDED.review,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: GetPlaylistReviewsByPlaylistId :many
+SELECT pr.*, au.username, au.name, au.profile_picture_url, au.color
+FROM playlist_review pr
+JOIN app_user au ON au.user_id = pr.user_id
+WHERE
+    pr.playlist_id = $1
+ORDER BY pr.updated_at DESC;
+
+-- name: DeletePlaylistReviewById :one
+DELETE FROM playlist_review
+WHERE id = $1 AND playlist_id = $2
+RETURNING *;
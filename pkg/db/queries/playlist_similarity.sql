@@ -0,0 +1,119 @@
+-- name: DeletePlaylistSimilarities :exec
+DELETE FROM playlist_similarity;
+
+-- name: RefreshPlaylistSimilarities :exec
+WITH
+    topic_counts AS (
+        SELECT playlist_id, COUNT(*) AS topic_count
+        FROM topic
+        GROUP BY playlist_id
+    ),
+    topic_overlap AS (
+        -- share of the playlist's topics that have a trigram match in the other one
+        SELECT
+            t1.playlist_id,
+            t2.playlist_id AS similar_playlist_id,
+            COUNT(DISTINCT t1.id)::float8 / GREATEST(MAX(tc.topic_count), 1) AS score
+        FROM topic t1
+        JOIN topic t2 ON t2.playlist_id <> t1.playlist_id AND t1.name % t2.name
+        JOIN topic_counts tc ON tc.playlist_id = t1.playlist_id
+        GROUP BY t1.playlist_id, t2.playlist_id
+    ),
+    opener_counts AS (
+        SELECT playlist_id, COUNT(*) AS user_count
+        FROM user_playlist
+        GROUP BY playlist_id
+    ),
+    co_occurrence AS (
+        -- share of the playlist's openers that also opened the other one
+        SELECT
+            up1.playlist_id,
+            up2.playlist_id AS similar_playlist_id,
+            COUNT(*)::float8 / GREATEST(MAX(oc.user_count), 1) AS score
+        FROM user_playlist up1
+        JOIN user_playlist up2 ON up2.user_id = up1.user_id AND up2.playlist_id <> up1.playlist_id
+        JOIN opener_counts oc ON oc.playlist_id = up1.playlist_id
+        GROUP BY up1.playlist_id, up2.playlist_id
+    ),
+    same_interest AS (
+        SELECT
+            p1.id AS playlist_id,
+            p2.id AS similar_playlist_id,
+            1.0::float8 AS score
+        FROM playlist p1
+        JOIN interest i ON i.id = p1.interest_id AND i.name <> 'None'
+        JOIN playlist p2 ON p2.interest_id = p1.interest_id AND p2.id <> p1.id
+    ),
+    candidates AS (
+        SELECT playlist_id, similar_playlist_id FROM topic_overlap
+        UNION
+        SELECT playlist_id, similar_playlist_id FROM co_occurrence
+        UNION
+        SELECT playlist_id, similar_playlist_id FROM same_interest
+    ),
+    scored AS (
+        SELECT
+            c.playlist_id,
+            c.similar_playlist_id,
+            COALESCE(tov.score, 0) AS topic_score,
+            COALESCE(si.score, 0) AS interest_score,
+            COALESCE(co.score, 0) AS co_occurrence_score,
+            @topic_weight::float8 * COALESCE(tov.score, 0)
+            + @interest_weight::float8 * COALESCE(si.score, 0)
+            + @co_occurrence_weight::float8 * COALESCE(co.score, 0) AS score
+        FROM candidates c
+        LEFT JOIN topic_overlap tov ON tov.playlist_id = c.playlist_id AND tov.similar_playlist_id = c.similar_playlist_id
+        LEFT JOIN same_interest si ON si.playlist_id = c.playlist_id AND si.similar_playlist_id = c.similar_playlist_id
+        LEFT JOIN co_occurrence co ON co.playlist_id = c.playlist_id AND co.similar_playlist_id = c.similar_playlist_id
+    ),
+    ranked AS (
+        SELECT
+            scored.*,
+            ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY score DESC) AS rn
+        FROM scored
+    )
+INSERT INTO
+    playlist_similarity (
+        playlist_id,
+        similar_playlist_id,
+        score,
+        topic_score,
+        interest_score,
+        co_occurrence_score
+    )
+SELECT
+    playlist_id,
+    similar_playlist_id,
+    score,
+    topic_score,
+    interest_score,
+    co_occurrence_score
+FROM ranked
+WHERE rn <= @max_per_playlist::int;
+
+-- name: GetSimilarPlaylists :many
+SELECT
+    p.id,
+    p.name,
+    p.description,
+    p.code,
+    p.thumbnail_url,
+    p.interest_id,
+    p.views,
+    p.average_rating,
+    p.rating_count,
+    p.created_at,
+    p.updated_at,
+    p.updated_by,
+    p.is_ai_gen,
+    ps.score,
+    (
+        SELECT COUNT(*)
+        FROM topic t
+        WHERE t.playlist_id = p.id
+    ) AS topics_count
+FROM playlist_similarity ps
+JOIN playlist p ON p.id = ps.similar_playlist_id
+WHERE ps.playlist_id = $1
+ORDER BY ps.score DESC
+LIMIT $2;
@@ -0,0 +1,119 @@
+-- name: CreatePlaylistView :exec
+INSERT INTO
+    playlist_view (
+        playlist_id,
+        user_id,
+        window_start
+    )
+VALUES ($1, $2, $3)
+ON CONFLICT (
+    playlist_id,
+    user_id,
+    window_start
+) DO NOTHING;
+
+-- name: RefreshPlaylistViewStats :exec
+-- counts the kept events of every playlist with views, events already rolled up
+-- stay in views_rolled_up and the views counted before deduplication are kept
+-- once as legacy_views
+INSERT INTO
+    playlist_view_stat (
+        playlist_id,
+        legacy_views,
+        views_24h,
+        views_7d,
+        views_all_time,
+        trending_score,
+        updated_at
+    )
+SELECT
+    p.id,
+    COALESCE(p.views, 0),
+    COUNT(pv.id) FILTER (WHERE pv.viewed_at > NOW() - INTERVAL '24 hours'),
+    COUNT(pv.id) FILTER (WHERE pv.viewed_at > NOW() - INTERVAL '7 days'),
+    COUNT(pv.id),
+    COALESCE(
+        SUM(
+            POWER(
+                0.5,
+                EXTRACT(EPOCH FROM (NOW() - pv.viewed_at)) / 3600.0 / @half_life_hours::float8
+            )
+        ),
+        0
+    ),
+    NOW()
+FROM playlist p
+LEFT JOIN playlist_view pv ON pv.playlist_id = p.id
+WHERE
+    pv.id IS NOT NULL
+    OR EXISTS (
+        SELECT 1
+        FROM playlist_view_stat s
+        WHERE s.playlist_id = p.id
+    )
+GROUP BY p.id
+ON CONFLICT (playlist_id) DO UPDATE
+SET
+    views_24h = EXCLUDED.views_24h,
+    views_7d = EXCLUDED.views_7d,
+    views_all_time = playlist_view_stat.views_rolled_up + EXCLUDED.views_all_time,
+    trending_score = EXCLUDED.trending_score,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: RollUpPlaylistViews :execrows
+-- deletes events past the 7d window and adds them to the rolled up count of
+-- their playlist, run after RefreshPlaylistViewStats so every playlist with
+-- events has a stat row
+WITH pruned AS (
+    DELETE FROM playlist_view pv
+    WHERE pv.viewed_at <= NOW() - INTERVAL '7 days'
+    RETURNING pv.playlist_id
+)
+UPDATE playlist_view_stat s
+SET
+    views_rolled_up = s.views_rolled_up + rolled.views
+FROM (
+    SELECT
+        playlist_id,
+        COUNT(*) AS views
+    FROM pruned
+    GROUP BY playlist_id
+) rolled
+WHERE rolled.playlist_id = s.playlist_id;
+
+-- name: GetTrendingPlaylists :many
+SELECT
+    p.id,
+    p.name,
+    p.description,
+    p.code,
+    p.thumbnail_url,
+    p.interest_id,
+    p.views,
+    p.average_rating,
+    p.rating_count,
+    s.views_24h,
+    s.views_7d,
+    s.trending_score,
+    p.created_at,
+    p.updated_at,
+    p.updated_by,
+    p.is_ai_gen,
+    (
+        SELECT COUNT(*)
+        FROM topic t
+        WHERE t.playlist_id = p.id
+    ) AS topics_count
+FROM playlist_view_stat s
+JOIN playlist p ON p.id = s.playlist_id
+WHERE s.trending_score > 0
+ORDER BY s.trending_score DESC
+LIMIT $1;
+
+-- name: SyncPlaylistViewsFromStats :exec
+UPDATE playlist p
+SET views = s.legacy_views + s.views_all_time
+FROM playlist_view_stat s
+WHERE
+    s.playlist_id = p.id
+    AND p.views IS DISTINCT FROM s.legacy_views + s.views_all_time;
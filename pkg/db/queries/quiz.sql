@@ -12,12 +12,220 @@ VALUES ($1, $2, $3, $4) RETURNING *;
 INSERT INTO
     "quiz_question" (
         quiz_id,
+        question_type,
         question,
         options,
         correct_option,
+        answer,
+        explanation,
+        reference_links,
         updated_by
     )
-VALUES ($1, $2, $3, $4, $5) RETURNING *;
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
 
 -- name: GetQuestionsByQuizId :many
-SELECT * FROM "quiz_question" WHERE quiz_id = $1;
\ No newline at end of file
+SELECT * FROM "quiz_question" WHERE quiz_id = $1;
+
+-- name: GetQuizById :one
+SELECT * FROM "quiz" WHERE id = $1;
+
+-- name: GetQuizByTopicId :one
+SELECT * FROM "quiz" WHERE topic_id = $1;
+
+-- name: InsertTopicQuiz :one
+-- returns no rows when the topic got its quiz from a concurrent generation
+INSERT INTO
+    "quiz" (
+        name,
+        topic_id,
+        play_count
+    )
+VALUES ($1, $2, 0)
+ON CONFLICT (topic_id) WHERE topic_id IS NOT NULL DO NOTHING
+RETURNING *;
+
+-- name: UpsertPlaylistExam :one
+-- returns the exam of a playlist, creating it on first use
+INSERT INTO
+    "quiz" (
+        name,
+        playlist_id,
+        play_count
+    )
+SELECT
+    p.name || ' Exam',
+    p.id,
+    0
+FROM playlist p
+WHERE p.id = $1
+ON CONFLICT (playlist_id) WHERE playlist_id IS NOT NULL DO UPDATE
+SET
+    name = EXCLUDED.name
+RETURNING *;
+
+-- name: UpdateQuizScoring :one
+UPDATE "quiz"
+SET
+    negative_marking = $2,
+    grade_bands = $3,
+    updated_by = $4,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateQuizTimeLimit :one
+UPDATE "quiz"
+SET
+    time_limit_seconds = $2,
+    updated_by = $3,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateQuizQuestionWeight :execrows
+UPDATE "quiz_question"
+SET
+    weight = $3,
+    updated_by = $4,
+    updated_at = NOW()
+WHERE id = $1
+AND quiz_id = $2;
+
+-- name: GetQuestionsByIds :many
+SELECT * FROM "quiz_question" WHERE id = ANY($1::uuid[]);
+
+-- name: GetPlaylistExamQuestions :many
+-- draws random questions from the bank of every topic quiz in a playlist,
+-- in topic order
+SELECT
+    id,
+    quiz_id,
+    question_type,
+    question,
+    options,
+    correct_option,
+    answer,
+    explanation,
+    reference_links,
+    weight,
+    updated_at,
+    created_at,
+    updated_by
+FROM (
+    SELECT
+        qq.*,
+        t.number AS topic_number,
+        ROW_NUMBER() OVER (PARTITION BY q.topic_id ORDER BY random()) AS pick
+    FROM quiz_question qq
+    JOIN quiz q ON q.id = qq.quiz_id
+    JOIN topic t ON t.id = q.topic_id
+    WHERE t.playlist_id = $1
+) bank
+WHERE pick <= $2
+ORDER BY topic_number, pick;
+
+-- name: IncrementQuizPlayCount :exec
+UPDATE "quiz"
+SET
+    play_count = COALESCE(play_count, 0) + 1,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateQuizResult :one
+INSERT INTO
+    "quiz_result" (
+        quiz_id,
+        user_id,
+        score,
+        grade,
+        correct_answers,
+        total_questions,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $2) RETURNING *;
+
+-- name: CreateQuizResultQuestion :exec
+INSERT INTO
+    "quiz_result_question" (
+        quiz_result_id,
+        quiz_question_id,
+        attempted_option,
+        attempted_answer,
+        is_correct,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetQuizResultsByUserId :many
+-- attempts of a learner, latest first
+SELECT
+    qr.id,
+    qr.quiz_id,
+    q.name AS quiz_name,
+    qr.score,
+    qr.grade,
+    qr.correct_answers,
+    qr.total_questions,
+    qr.created_at
+FROM quiz_result qr
+JOIN quiz q ON q.id = qr.quiz_id
+WHERE qr.user_id = $1
+ORDER BY qr.created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetQuizResultById :one
+SELECT
+    qr.id,
+    qr.quiz_id,
+    q.name AS quiz_name,
+    qr.score,
+    qr.grade,
+    qr.correct_answers,
+    qr.total_questions,
+    qr.created_at
+FROM quiz_result qr
+JOIN quiz q ON q.id = qr.quiz_id
+WHERE qr.id = $1
+AND qr.user_id = $2;
+
+-- name: GetQuizResultQuestions :many
+SELECT
+    qq.id,
+    qq.question_type,
+    qq.question,
+    qq.options,
+    qq.correct_option,
+    qq.answer,
+    qrq.attempted_option,
+    qrq.attempted_answer,
+    qrq.is_correct
+FROM quiz_result_question qrq
+JOIN quiz_question qq ON qq.id = qrq.quiz_question_id
+WHERE qrq.quiz_result_id = $1
+ORDER BY qq.created_at, qq.id;
+
+-- name: GetPlaylistQuizBestScores :many
+-- best score of a learner on the playlist exam and on every topic quiz of the
+-- playlist with questions, quizzes never taken have no best score
+SELECT
+    q.id AS quiz_id,
+    q.topic_id,
+    t.name AS topic_name,
+    MAX(qr.score)::double precision AS best_score
+FROM quiz q
+LEFT JOIN topic t ON t.id = q.topic_id
+LEFT JOIN quiz_result qr ON qr.quiz_id = q.id
+AND qr.user_id = @user_id::uuid
+WHERE
+    q.playlist_id = @playlist_id::uuid
+    OR (
+        t.playlist_id = @playlist_id::uuid
+        AND EXISTS (
+            SELECT 1
+            FROM quiz_question qq
+            WHERE qq.quiz_id = q.id
+        )
+    )
+GROUP BY q.id, q.topic_id, t.name, t.number
+ORDER BY t.number NULLS LAST, q.id;
@@ -0,0 +1,227 @@
+-- name: InsertQuizLeaderboardEntry :exec
+-- keeps the first graded attempt of a learner on a quiz, later ones are ignored
+INSERT INTO
+    quiz_leaderboard_entry (
+        quiz_id,
+        user_id,
+        quiz_result_id,
+        playlist_id,
+        score,
+        completion_seconds,
+        submitted_at
+    )
+SELECT
+    q.id,
+    @user_id::uuid,
+    @quiz_result_id::uuid,
+    COALESCE(q.playlist_id, t.playlist_id),
+    @score::double precision,
+    @completion_seconds::int,
+    @submitted_at::timestamp
+FROM quiz q
+LEFT JOIN topic t ON t.id = q.topic_id
+WHERE q.id = @quiz_id::uuid
+ON CONFLICT (quiz_id, user_id) DO NOTHING;
+
+-- name: GetQuizLeaderboard :many
+SELECT
+    ranked.user_id,
+    au.username,
+    au.name,
+    au.profile_picture_url,
+    au.color,
+    ranked.score,
+    ranked.completion_seconds,
+    ranked.submitted_at,
+    ranked.rank
+FROM (
+    SELECT
+        e.user_id,
+        e.score,
+        e.completion_seconds,
+        e.submitted_at,
+        RANK() OVER (ORDER BY e.score DESC, e.completion_seconds) AS rank
+    FROM quiz_leaderboard_entry e
+    WHERE e.quiz_id = @quiz_id::uuid
+) ranked
+JOIN app_user au ON au.user_id = ranked.user_id
+ORDER BY ranked.rank, ranked.submitted_at, ranked.user_id
+LIMIT @row_limit
+OFFSET @row_offset;
+
+-- name: GetQuizLeaderboardPosition :one
+-- rank of a learner on a quiz, counted the way GetQuizLeaderboard ranks
+SELECT
+    e.score,
+    e.completion_seconds,
+    e.submitted_at,
+    (
+        SELECT COUNT(*)
+        FROM quiz_leaderboard_entry o
+        WHERE o.quiz_id = e.quiz_id
+        AND (
+            o.score > e.score
+            OR (o.score = e.score AND o.completion_seconds < e.completion_seconds)
+        )
+    ) + 1 AS rank
+FROM quiz_leaderboard_entry e
+WHERE e.quiz_id = @quiz_id::uuid
+AND e.user_id = @user_id::uuid;
+
+-- name: RefreshPlaylistLeaderboardStats :exec
+-- ranks learners per playlist by the sum of their first attempt scores, the
+-- week board only counts attempts since @week_start
+INSERT INTO
+    playlist_leaderboard_stat (
+        playlist_id,
+        period,
+        user_id,
+        total_score,
+        quizzes_completed,
+        completion_seconds,
+        rank,
+        updated_at
+    )
+SELECT
+    totals.playlist_id,
+    totals.period,
+    totals.user_id,
+    totals.total_score,
+    totals.quizzes_completed,
+    totals.completion_seconds,
+    RANK() OVER (
+        PARTITION BY totals.playlist_id, totals.period
+        ORDER BY totals.total_score DESC, totals.completion_seconds
+    ),
+    @refreshed_at::timestamp
+FROM (
+    SELECT
+        e.playlist_id,
+        'all_time' AS period,
+        e.user_id,
+        SUM(e.score) AS total_score,
+        COUNT(*) AS quizzes_completed,
+        SUM(e.completion_seconds) AS completion_seconds
+    FROM quiz_leaderboard_entry e
+    WHERE e.playlist_id IS NOT NULL
+    GROUP BY e.playlist_id, e.user_id
+    UNION ALL
+    SELECT
+        e.playlist_id,
+        'week' AS period,
+        e.user_id,
+        SUM(e.score) AS total_score,
+        COUNT(*) AS quizzes_completed,
+        SUM(e.completion_seconds) AS completion_seconds
+    FROM quiz_leaderboard_entry e
+    WHERE e.playlist_id IS NOT NULL
+    AND e.submitted_at >= @week_start::timestamp
+    GROUP BY e.playlist_id, e.user_id
+) totals
+ON CONFLICT (playlist_id, period, user_id) DO UPDATE
+SET
+    total_score = EXCLUDED.total_score,
+    quizzes_completed = EXCLUDED.quizzes_completed,
+    completion_seconds = EXCLUDED.completion_seconds,
+    rank = EXCLUDED.rank,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteStalePlaylistLeaderboardStats :execrows
+-- drops rows the last refresh did not produce, like last week's board
+DELETE FROM playlist_leaderboard_stat
+WHERE updated_at < @refreshed_at::timestamp;
+
+-- name: GetPlaylistLeaderboard :many
+SELECT
+    s.user_id,
+    au.username,
+    au.name,
+    au.profile_picture_url,
+    au.color,
+    s.total_score,
+    s.quizzes_completed,
+    s.completion_seconds,
+    s.rank
+FROM playlist_leaderboard_stat s
+JOIN app_user au ON au.user_id = s.user_id
+WHERE s.playlist_id = @playlist_id::uuid
+AND s.period = @period::text
+ORDER BY s.rank, s.user_id
+LIMIT @row_limit
+OFFSET @row_offset;
+
+-- name: GetPlaylistLeaderboardPosition :one
+SELECT
+    s.total_score,
+    s.quizzes_completed,
+    s.completion_seconds,
+    s.rank
+FROM playlist_leaderboard_stat s
+WHERE s.playlist_id = @playlist_id::uuid
+AND s.period = @period::text
+AND s.user_id = @user_id::uuid;
+
+-- name: GetCommunityLeaderboard :many
+-- ranks the members of a community by the sum of their first attempt scores
+-- since @since, members without attempts are left out
+WITH ranked AS (
+    SELECT
+        ujc.user_id,
+        SUM(e.score)::double precision AS total_score,
+        COUNT(*)::int AS quizzes_completed,
+        SUM(e.completion_seconds)::bigint AS completion_seconds,
+        RANK() OVER (
+            ORDER BY SUM(e.score) DESC, SUM(e.completion_seconds)
+        ) AS rank
+    FROM user_joined_community ujc
+    JOIN quiz_leaderboard_entry e ON e.user_id = ujc.user_id
+    AND e.submitted_at >= @since::timestamp
+    -- only quizzes shared through a playlist count, free ones are left out
+    AND e.playlist_id IS NOT NULL
+    WHERE ujc.community_id = @community_id::uuid
+    GROUP BY ujc.user_id
+)
+SELECT
+    ranked.user_id,
+    au.username,
+    au.name,
+    au.profile_picture_url,
+    au.color,
+    ranked.total_score,
+    ranked.quizzes_completed,
+    ranked.completion_seconds,
+    ranked.rank
+FROM ranked
+JOIN app_user au ON au.user_id = ranked.user_id
+ORDER BY ranked.rank, ranked.user_id
+LIMIT @row_limit
+OFFSET @row_offset;
+
+-- name: GetCommunityLeaderboardPosition :one
+-- rank of a member, counted the way GetCommunityLeaderboard ranks
+WITH totals AS (
+    SELECT
+        ujc.user_id,
+        SUM(e.score) AS total_score,
+        COUNT(*) AS quizzes_completed,
+        SUM(e.completion_seconds) AS completion_seconds
+    FROM user_joined_community ujc
+    JOIN quiz_leaderboard_entry e ON e.user_id = ujc.user_id
+    AND e.submitted_at >= @since::timestamp
+    -- only quizzes shared through a playlist count, free ones are left out
+    AND e.playlist_id IS NOT NULL
+    WHERE ujc.community_id = @community_id::uuid
+    GROUP BY ujc.user_id
+)
+SELECT
+    t.total_score::double precision AS total_score,
+    t.quizzes_completed::int AS quizzes_completed,
+    t.completion_seconds::bigint AS completion_seconds,
+    (
+        SELECT COUNT(*)
+        FROM totals o
+        WHERE o.total_score > t.total_score
+        OR (o.total_score = t.total_score AND o.completion_seconds < t.completion_seconds)
+    ) + 1 AS rank
+FROM totals t
+WHERE t.user_id = @user_id::uuid;
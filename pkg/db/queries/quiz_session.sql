@@ -0,0 +1,53 @@
+-- name: CreateQuizSession :one
+INSERT INTO quiz_session (
+    quiz_id,
+    user_id,
+    question_ids,
+    option_orders,
+    started_at,
+    deadline_at,
+    updated_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $2)
+RETURNING *;
+
+-- name: GetQuizSessionById :one
+SELECT *
+FROM quiz_session
+WHERE id = $1
+AND user_id = $2;
+
+-- name: SaveQuizSessionAnswers :execrows
+-- merges autosaved answers into an open session, no rows means it is closed
+-- or its deadline passed
+UPDATE quiz_session
+SET
+    draft_answers = draft_answers || $3::jsonb,
+    updated_at = NOW()
+WHERE id = $1
+AND user_id = $2
+AND submitted_at IS NULL
+AND (deadline_at IS NULL OR deadline_at > $4);
+
+-- name: SubmitQuizSession :execrows
+-- closes an open session, no rows means it was already graded
+UPDATE quiz_session
+SET
+    submitted_at = $3,
+    quiz_result_id = $2,
+    updated_at = NOW()
+WHERE id = $1
+AND submitted_at IS NULL;
+
+-- name: GetExpiredQuizSessions :many
+-- open sessions past their deadline, or untimed ones left since before the
+-- abandon cutoff, oldest first
+SELECT *
+FROM quiz_session
+WHERE submitted_at IS NULL
+AND (
+    (deadline_at IS NOT NULL AND deadline_at < $1)
+    OR (deadline_at IS NULL AND started_at < $2)
+)
+ORDER BY started_at
+LIMIT $3;
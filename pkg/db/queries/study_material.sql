@@ -0,0 +1,96 @@
+-- name: UpsertStudyMaterialByTopicId :one
+INSERT INTO
+    study_material (
+        topic_id,
+        title,
+        content,
+        content_text,
+        is_generated,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (topic_id) DO UPDATE
+SET
+    title = EXCLUDED.title,
+    content = EXCLUDED.content,
+    content_text = EXCLUDED.content_text,
+    is_generated = EXCLUDED.is_generated,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: InsertGeneratedStudyMaterial :one
+-- a generated draft never replaces notes an admin saved in the meantime
+INSERT INTO
+    study_material (
+        topic_id,
+        title,
+        content,
+        content_text,
+        is_generated
+    )
+VALUES ($1, $2, $3, $4, true)
+ON CONFLICT (topic_id) DO NOTHING
+RETURNING *;
+
+-- name: GetStudyMaterialByTopicId :one
+SELECT
+    sm.id,
+    sm.topic_id,
+    sm.title,
+    sm.content,
+    sm.content_text,
+    sm.is_generated,
+    sm.updated_at,
+    sm.created_at,
+    sm.updated_by,
+    EXISTS (
+        SELECT 1
+        FROM user_study_material usm
+        WHERE usm.study_material_id = sm.id
+        AND usm.user_id = @user_id::uuid
+    ) AS is_bookmarked
+FROM study_material sm
+WHERE sm.topic_id = @topic_id::uuid;
+
+-- name: DeleteStudyMaterialBookmarks :exec
+DELETE FROM user_study_material
+WHERE study_material_id = $1;
+
+-- name: DeleteStudyMaterialById :execrows
+DELETE FROM study_material
+WHERE id = $1;
+
+-- name: BookmarkStudyMaterial :exec
+INSERT INTO
+    user_study_material (
+        study_material_id,
+        user_id,
+        updated_by
+    )
+VALUES ($1, $2, $2)
+ON CONFLICT (study_material_id, user_id) DO NOTHING;
+
+-- name: DeleteStudyMaterialBookmark :execrows
+DELETE FROM user_study_material
+WHERE study_material_id = $1
+AND user_id = $2;
+
+-- name: GetBookmarkedStudyMaterialsByUserId :many
+-- bookmarked notes without their content, latest bookmark first
+SELECT
+    sm.id,
+    sm.topic_id,
+    t.name AS topic_name,
+    t.playlist_id,
+    p.name AS playlist_name,
+    sm.title,
+    sm.content_text,
+    sm.updated_at,
+    usm.created_at AS bookmarked_at
+FROM user_study_material usm
+JOIN study_material sm ON sm.id = usm.study_material_id
+JOIN topic t ON t.id = sm.topic_id
+JOIN playlist p ON p.id = t.playlist_id
+WHERE usm.user_id = $1
+ORDER BY usm.created_at DESC;
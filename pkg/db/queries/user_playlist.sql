@@ -10,3 +10,18 @@ SET
     updated_at = NOW(),
     updated_by = EXCLUDED.updated_by
 RETURNING *;
+
+-- name: GetPlaylistProgressByUserId :many
+-- a topic counts as completed once the user watched any of its videos
+SELECT
+    t.playlist_id,
+    COUNT(DISTINCT t.id) AS total_topics,
+    COUNT(DISTINCT t.id) FILTER (
+        WHERE uwv.is_watched = true
+    ) AS completed_topics
+FROM topic t
+LEFT JOIN youtube_video yv ON yv.topic_id = t.id
+LEFT JOIN user_watched_video uwv ON uwv.youtube_video_id = yv.id
+AND uwv.user_id = @user_id::uuid
+WHERE t.playlist_id = ANY(@playlist_ids::uuid[])
+GROUP BY t.playlist_id;
@@ -0,0 +1,30 @@
+-- name: UpsertVideoProgress :exec
+-- watch time only grows and a watched video stays watched
+INSERT INTO user_watched_video (
+    user_id,
+    youtube_video_id,
+    position_seconds,
+    watch_seconds,
+    is_watched,
+    watched_at,
+    updated_by
+)
+VALUES ($1, $2, $3, $4, $5, CASE WHEN $5::boolean THEN NOW() END, $1)
+ON CONFLICT (user_id, youtube_video_id) DO UPDATE
+SET
+    position_seconds = EXCLUDED.position_seconds,
+    watch_seconds = GREATEST(user_watched_video.watch_seconds, EXCLUDED.watch_seconds),
+    is_watched = user_watched_video.is_watched OR EXCLUDED.is_watched,
+    watched_at = COALESCE(user_watched_video.watched_at, EXCLUDED.watched_at),
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by;
+
+-- name: GetVideoProgressByUserId :many
+SELECT
+    youtube_video_id,
+    position_seconds,
+    watch_seconds,
+    is_watched
+FROM user_watched_video
+WHERE user_id = @user_id::uuid
+AND youtube_video_id = ANY(@youtube_video_ids::uuid[]);
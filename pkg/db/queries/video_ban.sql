@@ -0,0 +1,24 @@
+-- name: CreateVideoBan :one
+-- banning an already banned target only updates the reason
+INSERT INTO video_ban (
+    kind,
+    value,
+    reason,
+    updated_by
+)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (kind, value) DO UPDATE
+SET
+    reason = EXCLUDED.reason,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: DeleteVideoBanById :execrows
+DELETE FROM video_ban
+WHERE id = $1;
+
+-- name: GetAllVideoBans :many
+SELECT *
+FROM video_ban
+ORDER BY created_at DESC;
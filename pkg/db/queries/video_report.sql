@@ -0,0 +1,60 @@
+-- name: UpsertVideoReport :exec
+-- reporting again replaces the learner's earlier report and reopens it
+INSERT INTO video_report (
+    topic_id,
+    video_id,
+    user_id,
+    reason,
+    note,
+    updated_by
+)
+VALUES ($1, $2, $3, $4, $5, $3)
+ON CONFLICT (user_id, topic_id, video_id) DO UPDATE
+SET
+    reason = EXCLUDED.reason,
+    note = EXCLUDED.note,
+    resolved_at = NULL,
+    resolution = NULL,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by;
+
+-- name: CountOpenVideoReports :one
+SELECT COUNT(*)
+FROM video_report
+WHERE topic_id = $1
+AND video_id = $2
+AND resolved_at IS NULL;
+
+-- name: GetOpenVideoReportQueue :many
+-- open reports grouped per topic video, most reported first
+SELECT
+    vr.topic_id,
+    vr.video_id,
+    t.name AS topic_name,
+    p.name AS playlist_name,
+    yv.title,
+    COALESCE(yv.is_hidden, false)::boolean AS is_hidden,
+    COUNT(*) AS reports_count,
+    ARRAY_AGG(vr.reason ORDER BY vr.updated_at DESC)::text[] AS reasons,
+    ARRAY_REMOVE(ARRAY_AGG(vr.note ORDER BY vr.updated_at DESC), NULL)::text[] AS notes,
+    MAX(vr.updated_at)::timestamp AS last_reported_at
+FROM video_report vr
+JOIN topic t ON t.id = vr.topic_id
+JOIN playlist p ON p.id = t.playlist_id
+LEFT JOIN youtube_video yv ON yv.topic_id = vr.topic_id AND yv.video_id = vr.video_id
+WHERE vr.resolved_at IS NULL
+GROUP BY vr.topic_id, vr.video_id, t.name, p.name, yv.title, yv.is_hidden
+ORDER BY reports_count DESC, last_reported_at DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ResolveVideoReports :execrows
+UPDATE video_report
+SET
+    resolved_at = NOW(),
+    resolution = $3,
+    updated_at = NOW(),
+    updated_by = $4
+WHERE topic_id = $1
+AND video_id = $2
+AND resolved_at IS NULL;
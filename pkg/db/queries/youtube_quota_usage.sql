@@ -0,0 +1,28 @@
+-- name: LockYoutubeQuota :exec
+-- serializes quota reservations for the rest of the transaction
+SELECT pg_advisory_xact_lock(hashtext('youtube_quota_usage'));
+
+-- name: GetYoutubeQuotaUnitsByDate :one
+SELECT COALESCE(SUM(units), 0)::int AS units
+FROM youtube_quota_usage
+WHERE usage_date = $1;
+
+-- name: AddYoutubeQuotaUsage :exec
+INSERT INTO youtube_quota_usage (
+    usage_date,
+    endpoint,
+    units,
+    calls
+)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (usage_date, endpoint) DO UPDATE
+SET
+    units = youtube_quota_usage.units + EXCLUDED.units,
+    calls = youtube_quota_usage.calls + 1,
+    updated_at = NOW();
+
+-- name: GetYoutubeQuotaUsageSince :many
+SELECT *
+FROM youtube_quota_usage
+WHERE usage_date >= $1
+ORDER BY usage_date DESC, endpoint;
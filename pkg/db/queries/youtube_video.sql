@@ -0,0 +1,135 @@
+-- name: UpsertYoutubeVideo :one
+INSERT INTO
+    youtube_video (
+        topic_id,
+        video_id,
+        provider,
+        title,
+        video_date,
+        channel_title,
+        channel_id,
+        thumbnail_url,
+        duration_seconds,
+        view_count,
+        like_count,
+        has_captions,
+        definition,
+        rank_score,
+        expiry_at,
+        is_manual,
+        updated_by
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+ON CONFLICT (topic_id, video_id) DO UPDATE
+SET
+    provider = EXCLUDED.provider,
+    title = EXCLUDED.title,
+    video_date = EXCLUDED.video_date,
+    channel_title = EXCLUDED.channel_title,
+    channel_id = EXCLUDED.channel_id,
+    thumbnail_url = EXCLUDED.thumbnail_url,
+    duration_seconds = EXCLUDED.duration_seconds,
+    view_count = EXCLUDED.view_count,
+    like_count = EXCLUDED.like_count,
+    has_captions = EXCLUDED.has_captions,
+    definition = EXCLUDED.definition,
+    rank_score = EXCLUDED.rank_score,
+    expiry_at = EXCLUDED.expiry_at,
+    -- a search finding a manually attached video must not demote it
+    is_manual = youtube_video.is_manual OR EXCLUDED.is_manual,
+    updated_at = NOW(),
+    -- refreshes save without a user, the admin who attached the video stays
+    updated_by = COALESCE(EXCLUDED.updated_by, youtube_video.updated_by)
+RETURNING *;
+
+-- name: GetTopicsWithExpiredVideos :many
+-- topics whose searched videos expired, oldest expiry first
+SELECT
+    t.id,
+    t.name,
+    p.name AS playlist_name,
+    MIN(yv.expiry_at)::timestamp AS oldest_expiry_at
+FROM topic t
+JOIN playlist p ON p.id = t.playlist_id
+JOIN youtube_video yv ON yv.topic_id = t.id
+WHERE yv.is_pinned = false
+AND yv.is_manual = false
+AND yv.expiry_at < NOW()
+GROUP BY t.id, t.name, p.name
+ORDER BY oldest_expiry_at
+LIMIT $1;
+
+-- name: DeleteStaleYoutubeVideosByTopicId :execrows
+-- removes searched videos missing from the latest search, watched ones stay for progress history
+DELETE FROM youtube_video yv
+WHERE yv.topic_id = @topic_id::uuid
+AND yv.is_pinned = false
+AND yv.is_manual = false
+AND NOT (yv.video_id = ANY(@keep_video_ids::text[]))
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_watched_video uwv
+    WHERE uwv.youtube_video_id = yv.id
+);
+
+-- name: ExtendYoutubeVideoExpiryByTopicId :exec
+UPDATE youtube_video
+SET
+    expiry_at = $2
+WHERE topic_id = $1
+AND is_pinned = false
+AND is_manual = false
+AND expiry_at < $2;
+
+-- name: PinYoutubeVideo :execrows
+-- pins one video of a topic and unpins the rest, nothing changes when the topic lacks the video
+UPDATE youtube_video yv
+SET
+    is_pinned = (yv.video_id = @video_id::text),
+    updated_at = NOW(),
+    updated_by = @updated_by::uuid
+WHERE yv.topic_id = @topic_id::uuid
+AND (yv.is_pinned = true OR yv.video_id = @video_id::text)
+AND EXISTS (
+    SELECT 1
+    FROM youtube_video pinned
+    WHERE pinned.topic_id = @topic_id::uuid
+    AND pinned.video_id = @video_id::text
+);
+
+-- name: UnpinYoutubeVideo :execrows
+UPDATE youtube_video
+SET
+    is_pinned = false,
+    updated_at = NOW(),
+    updated_by = $3
+WHERE topic_id = $1
+AND video_id = $2
+AND is_pinned = true;
+
+-- name: DeleteBannedYoutubeVideos :execrows
+-- removes videos matching a ban from every topic, watched ones stay for progress history and are hidden on read
+DELETE FROM youtube_video yv
+WHERE (
+    (@kind::text = 'video' AND yv.video_id = @value::text)
+    OR (@kind::text = 'channel' AND yv.channel_id = @value::text)
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_watched_video uwv
+    WHERE uwv.youtube_video_id = yv.id
+);
+
+-- name: GetYoutubeVideoByTopicId :one
+SELECT *
+FROM youtube_video
+WHERE topic_id = $1
+AND video_id = $2;
+
+-- name: SetYoutubeVideoHidden :execrows
+UPDATE youtube_video
+SET
+    is_hidden = $3,
+    updated_at = NOW()
+WHERE topic_id = $1
+AND video_id = $2;
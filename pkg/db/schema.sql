@@ -16,6 +16,8 @@ CREATE TABLE "playlist" (
     "views" int DEFAULT 0,
     "is_ai_gen" BOOLEAN NOT NULL DEFAULT FALSE,
     "thumbnail_url" TEXT,
+    "average_rating" double precision NOT NULL DEFAULT 0,
+    "rating_count" int NOT NULL DEFAULT 0,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "updated_by" uuid
@@ -31,6 +33,234 @@ CREATE TABLE "user_playlist" (
     PRIMARY KEY ("user_id", "playlist_id")
 );
 
+-- user rating and review of a playlist
+CREATE TABLE "playlist_review" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "playlist_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "rating" int NOT NULL CHECK (
+        "rating" BETWEEN 1 AND 5
+    ),
+    "review" TEXT,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    UNIQUE ("playlist_id", "user_id")
+);
+
+-- playlist open events, one row per user per dedup window
+CREATE TABLE "playlist_view" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "playlist_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "window_start" timestamp NOT NULL,
+    "viewed_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (
+        "playlist_id",
+        "user_id",
+        "window_start"
+    )
+);
+
+CREATE INDEX "playlist_view_viewed_at_idx" ON "playlist_view" ("viewed_at");
+
+-- playlist view aggregates, refreshed periodically from playlist_view
+CREATE TABLE "playlist_view_stat" (
+    "playlist_id" uuid PRIMARY KEY,
+    -- views counted before deduplication, captured when the row is created
+    "legacy_views" int NOT NULL DEFAULT 0,
+    "views_24h" int NOT NULL DEFAULT 0,
+    "views_7d" int NOT NULL DEFAULT 0,
+    -- deduplicated views, the rolled up ones plus the events still kept
+    "views_all_time" int NOT NULL DEFAULT 0,
+    -- events older than a week, deleted from playlist_view once counted here
+    "views_rolled_up" int NOT NULL DEFAULT 0,
+    "trending_score" double precision NOT NULL DEFAULT 0,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP
+);
+
+-- precomputed "similar playlists", refreshed periodically
+CREATE TABLE "playlist_similarity" (
+    "playlist_id" uuid NOT NULL,
+    "similar_playlist_id" uuid NOT NULL,
+    "score" double precision NOT NULL,
+    "topic_score" double precision NOT NULL DEFAULT 0,
+    "interest_score" double precision NOT NULL DEFAULT 0,
+    "co_occurrence_score" double precision NOT NULL DEFAULT 0,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (
+        "playlist_id",
+        "similar_playlist_id"
+    )
+);
+
+-- playlist_id requires prerequisite_playlist_id to be completed first
+CREATE TABLE "playlist_prerequisite" (
+    "playlist_id" uuid NOT NULL,
+    "prerequisite_playlist_id" uuid NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    PRIMARY KEY (
+        "playlist_id",
+        "prerequisite_playlist_id"
+    ),
+    CHECK (
+        "playlist_id" <> "prerequisite_playlist_id"
+    )
+);
+
+-- Learning Path Table, an ordered group of playlists
+CREATE TABLE "learning_path" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT,
+    "thumbnail_url" TEXT,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid
+);
+
+CREATE TABLE "learning_path_step" (
+    "learning_path_id" uuid NOT NULL,
+    "playlist_id" uuid NOT NULL,
+    "step_number" int NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    PRIMARY KEY (
+        "learning_path_id",
+        "playlist_id"
+    ),
+    UNIQUE (
+        "learning_path_id",
+        "step_number"
+    )
+);
+
+-- Certificate Table, issued once a user completes a playlist
+CREATE TABLE "certificate" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "code" VARCHAR(32) NOT NULL UNIQUE,
+    "user_id" uuid NOT NULL,
+    "playlist_id" uuid NOT NULL,
+    "learner_name" VARCHAR(255) NOT NULL,
+    "playlist_name" VARCHAR(255) NOT NULL,
+    "issued_at" timestamp NOT NULL,
+    "signature" TEXT NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    UNIQUE ("user_id", "playlist_id")
+);
+
+-- YouTube Data API quota units spent per day (Pacific time, as youtube resets) and endpoint
+CREATE TABLE "youtube_quota_usage" (
+    "usage_date" date NOT NULL,
+    "endpoint" VARCHAR(64) NOT NULL,
+    "units" int NOT NULL DEFAULT 0,
+    "calls" int NOT NULL DEFAULT 0,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("usage_date", "endpoint")
+);
+
+-- Video Ban Table
+CREATE TABLE "video_ban" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    -- video or channel
+    "kind" VARCHAR(16) NOT NULL,
+    -- video id or channel id, matched across every topic
+    "value" TEXT NOT NULL,
+    "reason" TEXT,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    UNIQUE ("kind", "value")
+);
+
+-- Video Report Table
+CREATE TABLE "video_report" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "topic_id" uuid NOT NULL,
+    -- provider video id, reports outlive the youtube_video row they point at
+    "video_id" TEXT NOT NULL,
+    "user_id" uuid NOT NULL,
+    "reason" VARCHAR(32) NOT NULL,
+    "note" TEXT,
+    -- set once an admin handles the report, open reports count towards hiding
+    "resolved_at" timestamp,
+    "resolution" VARCHAR(16),
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    UNIQUE ("user_id", "topic_id", "video_id")
+);
+
+CREATE INDEX "video_report_open_idx" ON "video_report" ("topic_id", "video_id") WHERE "resolved_at" IS NULL;
+
+-- Flashcard Deck Table
+CREATE TABLE "flashcard_deck" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    -- authored decks belong to a topic, decks of missed questions to their quiz
+    "topic_id" uuid,
+    "quiz_id" uuid,
+    "name" VARCHAR(255) NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    CHECK (("topic_id" IS NULL) <> ("quiz_id" IS NULL))
+);
+
+CREATE UNIQUE INDEX "flashcard_deck_topic_idx" ON "flashcard_deck" ("topic_id") WHERE "topic_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX "flashcard_deck_quiz_idx" ON "flashcard_deck" ("quiz_id") WHERE "quiz_id" IS NOT NULL;
+
+-- Flashcard Table
+CREATE TABLE "flashcard" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "deck_id" uuid NOT NULL,
+    -- markdown of both sides
+    "front" TEXT NOT NULL,
+    "back" TEXT NOT NULL,
+    -- question the card was derived from, null for authored cards
+    "quiz_question_id" uuid UNIQUE,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid
+);
+
+CREATE INDEX "flashcard_deck_id_idx" ON "flashcard" ("deck_id");
+
+-- User studied Flashcard Deck Table, every card of a studied deck is due once
+CREATE TABLE "user_flashcard_deck" (
+    "user_id" uuid NOT NULL,
+    "deck_id" uuid NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    PRIMARY KEY ("user_id", "deck_id")
+);
+
+-- User Flashcard Table, SM-2 review state of a card per learner
+CREATE TABLE "user_flashcard" (
+    "user_id" uuid NOT NULL,
+    "flashcard_id" uuid NOT NULL,
+    "ease_factor" double precision NOT NULL DEFAULT 2.5,
+    "interval_days" int NOT NULL DEFAULT 0,
+    "repetitions" int NOT NULL DEFAULT 0,
+    "lapses" int NOT NULL DEFAULT 0,
+    "last_grade" int,
+    "last_reviewed_at" timestamp,
+    "due_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid,
+    PRIMARY KEY ("user_id", "flashcard_id")
+);
+
+CREATE INDEX "user_flashcard_due_idx" ON "user_flashcard" ("user_id", "due_at");
+
 -- Topic Table
 CREATE TABLE "topic" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
@@ -42,17 +272,26 @@ CREATE TABLE "topic" (
     "updated_by" uuid
 );
 
+CREATE INDEX "topic_name_trgm_idx" ON "topic" USING gin ("name" gin_trgm_ops);
+
 -- Study Material Table
 CREATE TABLE "study_material" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
     "topic_id" uuid NOT NULL UNIQUE,
     "title" VARCHAR(255),
+    -- markdown notes of the topic
     "content" TEXT,
+    -- content without markdown, kept for search
+    "content_text" TEXT,
+    -- drafted by the notes generator and not yet edited by an admin
+    "is_generated" boolean NOT NULL DEFAULT false,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "updated_by" uuid
 );
 
+CREATE INDEX "study_material_content_text_trgm_idx" ON "study_material" USING gin ("content_text" gin_trgm_ops);
+
 -- User saved study Material Table
 CREATE TABLE "user_study_material" (
     "study_material_id" uuid NOT NULL,
@@ -71,20 +310,48 @@ CREATE TABLE "youtube_video" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
     "topic_id" uuid NOT NULL,
     "video_id" TEXT NOT NULL,
+    -- source the video_id belongs to, youtube or peertube
+    "provider" VARCHAR(32) NOT NULL DEFAULT 'youtube',
     "title" VARCHAR(255),
     "video_date" timestamp,
     "channel_title" VARCHAR(255),
+    -- provider's channel identifier, what channel bans match on
+    "channel_id" TEXT,
     "thumbnail_url" TEXT,
+    -- details from the provider's video lookup, null when it has none
+    "duration_seconds" int,
+    "view_count" bigint,
+    "like_count" bigint,
+    "has_captions" boolean NOT NULL DEFAULT false,
+    "definition" VARCHAR(8),
+    -- score of the ranking function when the video was saved, higher is better
+    "rank_score" double precision NOT NULL DEFAULT 0,
     "expiry_at" timestamp,
+    -- pinned videos are picked by an admin and survive cache refreshes
+    "is_pinned" boolean NOT NULL DEFAULT false,
+    -- attached by an admin rather than found by a search, refreshes keep them
+    "is_manual" boolean NOT NULL DEFAULT false,
+    -- hidden after enough learner reports until an admin dismisses them
+    "is_hidden" boolean NOT NULL DEFAULT false,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
-    "updated_by" uuid
+    "updated_by" uuid,
+    UNIQUE ("topic_id", "video_id")
 );
 
+CREATE INDEX "youtube_video_expiry_at_idx" ON "youtube_video" ("expiry_at");
+
 -- Watched Video Table
 CREATE TABLE "user_watched_video" (
     "user_id" uuid NOT NULL,
     "youtube_video_id" uuid NOT NULL,
+    -- latest playback position, where the learner resumes
+    "position_seconds" int NOT NULL DEFAULT 0,
+    -- total time spent playing the video, replays included
+    "watch_seconds" int NOT NULL DEFAULT 0,
+    -- set once the watched share of the video crosses the threshold, never unset
+    "is_watched" boolean NOT NULL DEFAULT false,
+    "watched_at" timestamp,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "updated_by" uuid,
@@ -97,18 +364,56 @@ CREATE TABLE "quiz" (
     "name" VARCHAR(255),
     "thumbnail_url" TEXT,
     "play_count" int,
+    -- topic quizzes hold the question bank of a topic, playlist quizzes are
+    -- exams drawn from those banks, free quizzes have neither
+    "topic_id" uuid,
+    "playlist_id" uuid,
+    -- sessions of timed quizzes must be submitted within this many seconds
+    "time_limit_seconds" int CHECK ("time_limit_seconds" > 0),
+    -- share of a question's weight taken off for a wrong answer
+    "negative_marking" double precision NOT NULL DEFAULT 0 CHECK ("negative_marking" BETWEEN 0 AND 1),
+    -- [{"grade": "A", "minPercent": 80}], empty uses the default bands
+    "grade_bands" jsonb NOT NULL DEFAULT '[]',
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
-    "updated_by" uuid
+    "updated_by" uuid,
+    CHECK ("topic_id" IS NULL OR "playlist_id" IS NULL)
+);
+
+CREATE UNIQUE INDEX "quiz_topic_idx" ON "quiz" ("topic_id") WHERE "topic_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX "quiz_playlist_idx" ON "quiz" ("playlist_id") WHERE "playlist_id" IS NOT NULL;
+
+-- Quiz Question Type Enum
+CREATE TYPE quiz_question_type AS ENUM (
+  'single_choice',
+  'multi_select',
+  'true_false',
+  'numeric',
+  'short_text',
+  'ordering'
 );
 
 -- Quiz Question Table
 CREATE TABLE "quiz_question" (
   "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
   "quiz_id" uuid NOT NULL,
+  "question_type" quiz_question_type NOT NULL DEFAULT 'single_choice',
   "question" TEXT,
   "options" TEXT[],  -- Arrays of strings for options
+  -- single choice and true/false questions, option numbers start at 1
   "correct_option" int,
+  -- answer key of the other types, {"options": [1, 3]} for multi select and
+  -- the correct sequence of ordering questions, {"value": 9.8, "tolerance": 0.1}
+  -- for numeric ones, {"accepted": ["..."], "pattern": "..."} for short text
+  "answer" jsonb NOT NULL DEFAULT '{}',
+  -- why the answer is right, shown to learners who got the question wrong
+  "explanation" TEXT,
+  -- where the topic covers the question, [{"type": "video", "videoId": "...",
+  -- "provider": "youtube", "startSeconds": 95}] or [{"type": "study_material",
+  -- "topicId": "...", "heading": "..."}]
+  "reference_links" jsonb NOT NULL DEFAULT '[]',
+  "weight" double precision NOT NULL DEFAULT 1 CHECK ("weight" > 0),
   "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
   "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
   "updated_by" uuid
@@ -119,23 +424,96 @@ CREATE TABLE "quiz_result" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
     "quiz_id" uuid NOT NULL,
     "user_id" uuid NOT NULL,
+    -- percentage of the total marks
     "score" decimal,
+    "grade" VARCHAR(4),
+    "correct_answers" int NOT NULL DEFAULT 0,
+    "total_questions" int NOT NULL DEFAULT 0,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "updated_by" uuid
 );
 
+CREATE INDEX "quiz_result_user_idx" ON "quiz_result" ("user_id", "created_at" DESC);
+
 -- Quiz Result Question Table
 CREATE TABLE "quiz_result_question" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
     "quiz_result_id" uuid NOT NULL,
     "quiz_question_id" uuid NOT NULL,
     "attempted_option" int,
+    -- the full answer, same shape as the session drafts
+    "attempted_answer" jsonb NOT NULL DEFAULT '{}',
+    "is_correct" boolean NOT NULL DEFAULT false,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_by" uuid
+);
+
+-- Quiz Session Table, the questions served to a learner for one attempt
+CREATE TABLE "quiz_session" (
+    "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    "quiz_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "question_ids" uuid[] NOT NULL,
+    -- {"<question id>": [3, 1, 4, 2]}, the original option numbers in the
+    -- order they were served
+    "option_orders" jsonb NOT NULL DEFAULT '{}',
+    -- {"<question id>": {"option": 2}}, autosaved answers by original option
+    -- number, or {"options": [...]}, {"value": ...} and {"text": ...} by type
+    "draft_answers" jsonb NOT NULL DEFAULT '{}',
+    "started_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- timed sessions only, answers after it are not accepted
+    "deadline_at" timestamp,
+    -- set once the attempt is graded, a session is graded only once
+    "submitted_at" timestamp,
+    "quiz_result_id" uuid,
     "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
     "updated_by" uuid
 );
 
+CREATE INDEX "quiz_session_open_idx" ON "quiz_session" ("started_at") WHERE "submitted_at" IS NULL;
+
+-- Quiz Leaderboard Entry Table, the first graded attempt of a learner on a
+-- quiz, retakes never replace it
+CREATE TABLE "quiz_leaderboard_entry" (
+    "quiz_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "quiz_result_id" uuid NOT NULL,
+    -- playlist the quiz counts towards, the exam's own or the topic's
+    "playlist_id" uuid,
+    -- percentage of the total marks
+    "score" double precision NOT NULL,
+    -- from the session start to its submission, ties rank the faster first
+    "completion_seconds" int NOT NULL,
+    "submitted_at" timestamp NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("quiz_id", "user_id")
+);
+
+CREATE INDEX "quiz_leaderboard_entry_rank_idx" ON "quiz_leaderboard_entry" ("quiz_id", "score" DESC, "completion_seconds");
+
+CREATE INDEX "quiz_leaderboard_entry_playlist_idx" ON "quiz_leaderboard_entry" ("playlist_id", "submitted_at") WHERE "playlist_id" IS NOT NULL;
+
+CREATE INDEX "quiz_leaderboard_entry_user_idx" ON "quiz_leaderboard_entry" ("user_id", "submitted_at");
+
+-- playlist leaderboards, refreshed periodically from quiz_leaderboard_entry
+CREATE TABLE "playlist_leaderboard_stat" (
+    "playlist_id" uuid NOT NULL,
+    -- 'week' counts first attempts of the current week, 'all_time' every one
+    "period" VARCHAR(16) NOT NULL,
+    "user_id" uuid NOT NULL,
+    "total_score" double precision NOT NULL,
+    "quizzes_completed" int NOT NULL,
+    "completion_seconds" bigint NOT NULL,
+    "rank" int NOT NULL,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("playlist_id", "period", "user_id")
+);
+
+CREATE INDEX "playlist_leaderboard_stat_rank_idx" ON "playlist_leaderboard_stat" ("playlist_id", "period", "rank");
+
 -- Community Table
 CREATE TABLE "community" (
     "id" uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
@@ -264,6 +642,75 @@ ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
 ALTER TABLE "user_playlist"
 ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
 
+ALTER TABLE "playlist_review"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_review"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "playlist_view"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_view"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "playlist_view_stat"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_similarity"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_similarity"
+ADD FOREIGN KEY ("similar_playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_prerequisite"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_prerequisite"
+ADD FOREIGN KEY ("prerequisite_playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "learning_path_step"
+ADD FOREIGN KEY ("learning_path_id") REFERENCES "learning_path" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "learning_path_step"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "certificate"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "certificate"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "video_report"
+ADD FOREIGN KEY ("topic_id") REFERENCES "topic" ("id");
+
+ALTER TABLE "video_report"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "flashcard_deck"
+ADD FOREIGN KEY ("topic_id") REFERENCES "topic" ("id");
+
+ALTER TABLE "flashcard_deck"
+ADD FOREIGN KEY ("quiz_id") REFERENCES "quiz" ("id");
+
+ALTER TABLE "flashcard"
+ADD FOREIGN KEY ("deck_id") REFERENCES "flashcard_deck" ("id");
+
+ALTER TABLE "flashcard"
+ADD FOREIGN KEY ("quiz_question_id") REFERENCES "quiz_question" ("id");
+
+ALTER TABLE "user_flashcard_deck"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "user_flashcard_deck"
+ADD FOREIGN KEY ("deck_id") REFERENCES "flashcard_deck" ("id");
+
+ALTER TABLE "user_flashcard"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "user_flashcard"
+ADD FOREIGN KEY ("flashcard_id") REFERENCES "flashcard" ("id");
+
 ALTER TABLE "user_token"
 ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
 
@@ -288,6 +735,12 @@ ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
 ALTER TABLE "user_watched_video"
 ADD FOREIGN KEY ("youtube_video_id") REFERENCES "youtube_video" ("id");
 
+ALTER TABLE "quiz"
+ADD FOREIGN KEY ("topic_id") REFERENCES "topic" ("id");
+
+ALTER TABLE "quiz"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
 ALTER TABLE "quiz_question"
 ADD FOREIGN KEY ("quiz_id") REFERENCES "quiz" ("id");
 
@@ -303,6 +756,33 @@ ADD FOREIGN KEY ("quiz_result_id") REFERENCES "quiz_result" ("id");
 ALTER TABLE "quiz_result_question"
 ADD FOREIGN KEY ("quiz_question_id") REFERENCES "quiz_question" ("id");
 
+ALTER TABLE "quiz_session"
+ADD FOREIGN KEY ("quiz_id") REFERENCES "quiz" ("id");
+
+ALTER TABLE "quiz_session"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "quiz_session"
+ADD FOREIGN KEY ("quiz_result_id") REFERENCES "quiz_result" ("id");
+
+ALTER TABLE "quiz_leaderboard_entry"
+ADD FOREIGN KEY ("quiz_id") REFERENCES "quiz" ("id");
+
+ALTER TABLE "quiz_leaderboard_entry"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
+ALTER TABLE "quiz_leaderboard_entry"
+ADD FOREIGN KEY ("quiz_result_id") REFERENCES "quiz_result" ("id");
+
+ALTER TABLE "quiz_leaderboard_entry"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_leaderboard_stat"
+ADD FOREIGN KEY ("playlist_id") REFERENCES "playlist" ("id");
+
+ALTER TABLE "playlist_leaderboard_stat"
+ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
+
 ALTER TABLE "message"
 ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id");
 
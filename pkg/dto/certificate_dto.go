@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CertificateDTO struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	PlaylistID   uuid.UUID `json:"playlistId"`
+	PlaylistName string    `json:"playlistName"`
+	LearnerName  string    `json:"learnerName"`
+	IssuedAt     time.Time `json:"issuedAt"`
+}
+
+// CertificatePayload is the exact document that gets signed, field order is part of the signature
+type CertificatePayload struct {
+	Code         string    `json:"code"`
+	LearnerName  string    `json:"learnerName"`
+	PlaylistID   uuid.UUID `json:"playlistId"`
+	PlaylistName string    `json:"playlistName"`
+	IssuedAt     time.Time `json:"issuedAt"`
+}
+
+type CertificateVerificationDTO struct {
+	Valid        bool      `json:"valid"`
+	Code         string    `json:"code"`
+	LearnerName  string    `json:"learnerName"`
+	PlaylistID   uuid.UUID `json:"playlistId"`
+	PlaylistName string    `json:"playlistName"`
+	IssuedAt     time.Time `json:"issuedAt"`
+	Payload      string    `json:"payload"`
+	Signature    string    `json:"signature"`
+	Algorithm    string    `json:"algorithm"`
+}
@@ -22,6 +22,7 @@ type CommunityDTO struct {
 	ID           uuid.UUID `json:"id"`
 	Title        string    `json:"title"`
 	About        string    `json:"about"`
+	AboutHTML    string    `json:"aboutHtml"`
 	ThumbnailUrl string    `json:"thumbnailUrl"`
 	LogoUrl      string    `json:"logoUrl"`
 	UpdatedAt    time.Time `json:"updatedAt"`
@@ -47,9 +48,10 @@ type UserMessageDTO struct {
 }
 
 type MessageDTO struct {
-	ID        uuid.UUID `json:"id"`
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
+	ID          uuid.UUID `json:"id"`
+	Content     string    `json:"content"`
+	ContentHTML string    `json:"contentHtml"`
+	Timestamp   time.Time `json:"timestamp"`
 }
 
 type SocketMessageDTO struct {
@@ -62,5 +64,6 @@ type SocketMessageDTO struct {
 	UserColor      models.Color `json:"userColor"`
 	CommunityID    uuid.UUID    `json:"communityId"`
 	Content        string       `json:"content"`
+	ContentHTML    string       `json:"contentHtml"`
 	Timestamp      time.Time    `json:"timestamp"`
 }
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FlashcardDTO struct {
+	ID        uuid.UUID `json:"id"`
+	DeckID    uuid.UUID `json:"deckId"`
+	Front     string    `json:"front"`
+	FrontHTML string    `json:"frontHtml"`
+	Back      string    `json:"back"`
+	BackHTML  string    `json:"backHtml"`
+	IsDerived bool      `json:"isDerived"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FlashcardDeckDTO struct {
+	ID         uuid.UUID      `json:"id"`
+	TopicID    uuid.UUID      `json:"topicId"`
+	Name       string         `json:"name"`
+	IsStudying bool           `json:"isStudying"`
+	Cards      []FlashcardDTO `json:"cards"`
+}
+
+type FlashcardRequest struct {
+	Front string `json:"front" binding:"required"`
+	Back  string `json:"back" binding:"required"`
+}
+
+type DueFlashcardDTO struct {
+	ID           uuid.UUID `json:"id"`
+	DeckID       uuid.UUID `json:"deckId"`
+	DeckName     string    `json:"deckName"`
+	TopicID      uuid.UUID `json:"topicId"`
+	Front        string    `json:"front"`
+	FrontHTML    string    `json:"frontHtml"`
+	Back         string    `json:"back"`
+	BackHTML     string    `json:"backHtml"`
+	IsNew        bool      `json:"isNew"`
+	Repetitions  int       `json:"repetitions"`
+	IntervalDays int       `json:"intervalDays"`
+	EaseFactor   float64   `json:"easeFactor"`
+	DueAt        time.Time `json:"dueAt"`
+}
+
+type ReviewFlashcardRequest struct {
+	// pointer so a 0 grade passes the required check
+	Grade *int `json:"grade" binding:"required,min=0,max=5"`
+}
+
+type FlashcardReviewDTO struct {
+	CardID       uuid.UUID `json:"cardId"`
+	Grade        int       `json:"grade"`
+	Repetitions  int       `json:"repetitions"`
+	IntervalDays int       `json:"intervalDays"`
+	EaseFactor   float64   `json:"easeFactor"`
+	Lapses       int       `json:"lapses"`
+	DueAt        time.Time `json:"dueAt"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateLearningPathRequest struct {
+	Name         string      `json:"name"         binding:"required"`
+	Description  string      `json:"description"`
+	ThumbnailURL string      `json:"thumbnailUrl"`
+	PlaylistIDs  []uuid.UUID `json:"playlistIds"  binding:"required,min=1"`
+}
+
+type UpdateLearningPathStepsRequest struct {
+	PlaylistIDs []uuid.UUID `json:"playlistIds" binding:"required,min=1"`
+}
+
+type LearningPathDTO struct {
+	ID             uuid.UUID             `json:"id"`
+	Name           string                `json:"name"`
+	Description    string                `json:"description"`
+	ThumbnailURL   string                `json:"thumbnailUrl"`
+	StepsCount     int                   `json:"stepsCount"`
+	CompletedSteps int                   `json:"completedSteps"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+	Steps          []LearningPathStepDTO `json:"steps,omitempty"`
+}
+
+type LearningPathStepDTO struct {
+	StepNumber      int       `json:"stepNumber"`
+	PlaylistID      uuid.UUID `json:"playlistId"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Code            string    `json:"code"`
+	ThumbnailURL    string    `json:"thumbnailUrl"`
+	AverageRating   float64   `json:"averageRating"`
+	RatingCount     int       `json:"ratingCount"`
+	TotalTopics     int       `json:"totalTopics"`
+	CompletedTopics int       `json:"completedTopics"`
+	IsCompleted     bool      `json:"isCompleted"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type PeerTubeSearchResponse struct {
+	Total int             `json:"total"`
+	Data  []PeerTubeVideo `json:"data"`
+}
+
+type PeerTubeVideo struct {
+	UUID          string          `json:"uuid"`
+	ShortUUID     string          `json:"shortUUID"`
+	Name          string          `json:"name"`
+	PublishedAt   time.Time       `json:"publishedAt"`
+	ThumbnailPath string          `json:"thumbnailPath"`
+	Duration      int             `json:"duration"`
+	Views         int64           `json:"views"`
+	Likes         int64           `json:"likes"`
+	Channel       PeerTubeChannel `json:"channel"`
+}
+
+type PeerTubeChannel struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
@@ -1,6 +1,11 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/google/uuid"
+)
 
 type CreatePlaylistRequest struct {
 	Name         string   `json:"name"         binding:"required"`
@@ -12,18 +17,22 @@ type CreatePlaylistRequest struct {
 }
 
 type PlaylistDetailsDTO struct {
-	ID           string          `json:"id"`
-	Name         string          `json:"name"`
-	Description  string          `json:"description"`
-	InterestID   string          `json:"interestId"`
-	ThumbnailURL string          `json:"thumbnailUrl"`
-	Views        int             `json:"views"`
-	Code         string          `json:"code"`
-	CreatedAt    time.Time       `json:"createdAt"`
-	UpdatedAt    time.Time       `json:"updatedAt"`
-	UpdatedBy    string          `json:"updatedBy"`
-	IsAIGen      bool            `json:"isAIGen"`
-	Topics       []TopicsMiniDTO `json:"topics"`
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	InterestID    string          `json:"interestId"`
+	ThumbnailURL  string          `json:"thumbnailUrl"`
+	Views         int             `json:"views"`
+	AverageRating float64         `json:"averageRating"`
+	RatingCount   int             `json:"ratingCount"`
+	Code          string          `json:"code"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+	UpdatedBy     string          `json:"updatedBy"`
+	IsAIGen       bool            `json:"isAIGen"`
+	Topics        []TopicsMiniDTO `json:"topics"`
+
+	UnmetPrerequisites []PlaylistPrerequisiteDTO `json:"unmetPrerequisites,omitempty"`
 }
 
 type TopicsMiniDTO struct {
@@ -34,45 +43,143 @@ type TopicsMiniDTO struct {
 }
 
 type PlaylistPreviewDTO struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	InterestID   string    `json:"interestId"`
-	ThumbnailURL string    `json:"thumbnailUrl"`
-	Views        int       `json:"views"`
-	Code         string    `json:"code"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-	UpdatedBy    string    `json:"updatedBy"`
-	IsAIGen      bool      `json:"isAIGen"`
-	TopicsCount  int       `json:"topicsCount,omitempty"`
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	InterestID      string    `json:"interestId"`
+	ThumbnailURL    string    `json:"thumbnailUrl"`
+	Views           int       `json:"views"`
+	AverageRating   float64   `json:"averageRating"`
+	RatingCount     int       `json:"ratingCount"`
+	Views24h        int       `json:"views24h,omitempty"`
+	Views7d         int       `json:"views7d,omitempty"`
+	TrendingScore   float64   `json:"trendingScore,omitempty"`
+	SimilarityScore float64   `json:"similarityScore,omitempty"`
+	Code            string    `json:"code"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	UpdatedBy       string    `json:"updatedBy"`
+	IsAIGen         bool      `json:"isAIGen"`
+	TopicsCount     int       `json:"topicsCount,omitempty"`
 }
 
 type VideoDataDTO struct {
-	ID           string    `json:"id"`
-	TopicID      string    `json:"topicId"`
-	VideoID      string    `json:"videoId"`
-	Title        string    `json:"title"`
-	VideoDate    time.Time `json:"videoPublishedAt"`
-	ChannelTitle string    `json:"channelTitle"`
-	ThumbnailURL string    `json:"thumbnailUrl"`
-	ExpiryAt     time.Time `json:"expiryAt"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-	UpdatedBy    string    `json:"updatedBy"`
+	ID              string    `json:"id"`
+	TopicID         string    `json:"topicId"`
+	VideoID         string    `json:"videoId"`
+	Provider        string    `json:"provider"`
+	Title           string    `json:"title"`
+	VideoDate       time.Time `json:"videoPublishedAt"`
+	ChannelTitle    string    `json:"channelTitle"`
+	ChannelID       string    `json:"channelId"`
+	ThumbnailURL    string    `json:"thumbnailUrl"`
+	DurationSeconds int       `json:"durationSeconds"`
+	ViewCount       int64     `json:"viewCount"`
+	LikeCount       int64     `json:"likeCount"`
+	HasCaptions     bool      `json:"hasCaptions"`
+	Definition      string    `json:"definition"`
+	RankScore       float64   `json:"rankScore"`
+	ExpiryAt        time.Time `json:"expiryAt"`
+	IsPinned        bool      `json:"isPinned"`
+	IsManual        bool      `json:"isManual"`
+	IsHidden        bool      `json:"isHidden"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	UpdatedBy       string    `json:"updatedBy"`
+
+	// Playback of the requesting learner, zero for admins
+	ResumePositionSeconds int  `json:"resumePositionSeconds"`
+	WatchSeconds          int  `json:"watchSeconds"`
+	IsWatched             bool `json:"isWatched"`
 }
 
 type VideoMiniDTO struct {
 	VideoID      string    `json:"videoId"`
+	Provider     string    `json:"provider,omitempty"`
 	Title        string    `json:"title"`
 	VideoDate    time.Time `json:"videoPublishedAt"`
 	ThumbnailURL string    `json:"thumbnailUrl"`
 	ChannelTitle string    `json:"channelTitle"`
+	ChannelID    string    `json:"channelId,omitempty"`
+
+	// Details below are filled by providers that can look them up
+	DurationSeconds int     `json:"durationSeconds,omitempty"`
+	ViewCount       int64   `json:"viewCount,omitempty"`
+	LikeCount       int64   `json:"likeCount,omitempty"`
+	HasCaptions     bool    `json:"hasCaptions,omitempty"`
+	Definition      string  `json:"definition,omitempty"`
+	RankScore       float64 `json:"rankScore,omitempty"`
+}
+
+type AddTopicVideoRequest struct {
+	// youtube url or bare video id
+	URL string `json:"url" binding:"required"`
+	Pin bool   `json:"pin"`
+}
+
+type CreateVideoBanRequest struct {
+	Kind string `json:"kind" binding:"required,oneof=video channel"`
+	// video id, youtube url or channel id
+	Value  string `json:"value" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type VideoBanDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedBy string    `json:"updatedBy"`
+}
+
+type VideoProgressRequest struct {
+	PositionSeconds int `json:"positionSeconds" binding:"min=0"`
+	// total seconds played so far, replays included
+	WatchSeconds int `json:"watchSeconds" binding:"min=0"`
+	// player reported length, used when the provider gave none
+	DurationSeconds int `json:"durationSeconds" binding:"min=0"`
+}
+
+type VideoProgressDTO struct {
+	VideoID         string `json:"videoId"`
+	PositionSeconds int    `json:"positionSeconds"`
+	WatchSeconds    int    `json:"watchSeconds"`
+	IsWatched       bool   `json:"isWatched"`
+}
+
+type ReportVideoRequest struct {
+	Reason string `json:"reason" binding:"required,oneof=broken off_topic low_quality wrong_language inappropriate other"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+type VideoReportResultDTO struct {
+	VideoID      string `json:"videoId"`
+	ReportsCount int    `json:"reportsCount"`
+	IsHidden     bool   `json:"isHidden"`
+}
+
+type ResolveVideoReportRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss hide ban"`
+}
+
+type VideoReportQueueItemDTO struct {
+	TopicID        uuid.UUID      `json:"topicId"`
+	VideoID        string         `json:"videoId"`
+	TopicName      string         `json:"topicName"`
+	PlaylistName   string         `json:"playlistName"`
+	Title          string         `json:"title"`
+	IsHidden       bool           `json:"isHidden"`
+	ReportsCount   int            `json:"reportsCount"`
+	Reasons        map[string]int `json:"reasons"`
+	Notes          []string       `json:"notes"`
+	LastReportedAt time.Time      `json:"lastReportedAt"`
 }
 
 type GroupedVideoDataResponse struct {
 	Video      VideoDataDTO   `json:"video"`
 	MoreVideos []VideoDataDTO `json:"moreVideos"`
+	Status     string         `json:"status,omitempty"`
 }
 
 type GeneratedPlaylist struct {
@@ -84,3 +191,76 @@ type GeneratedPlaylist struct {
 type GeneratePlaylistParams struct {
 	Title string `json:"subject"`
 }
+
+type PlaylistBundle struct {
+	Version  int                    `json:"version"`
+	Playlist PlaylistBundlePlaylist `json:"playlist"`
+}
+
+type PlaylistBundlePlaylist struct {
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	DomainName   string                `json:"domainName"`
+	ThumbnailURL string                `json:"thumbnailUrl"`
+	IsAIGen      bool                  `json:"isAIGen"`
+	Topics       []PlaylistBundleTopic `json:"topics"`
+}
+
+type PlaylistBundleTopic struct {
+	Name          string                  `json:"name"`
+	TopicNumber   int                     `json:"topicNumber"`
+	Videos        []PlaylistBundleVideo   `json:"videos"`
+	StudyMaterial *PlaylistBundleMaterial `json:"studyMaterial,omitempty"`
+}
+
+// PlaylistBundleVideo is a video of an exported topic, pinned and manually
+// attached videos are restored as such so curation survives a round trip
+type PlaylistBundleVideo struct {
+	VideoMiniDTO
+	Pinned bool `json:"pinned,omitempty"`
+	Manual bool `json:"manual,omitempty"`
+}
+
+type PlaylistBundleMaterial struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+type PlaylistImportError struct {
+	Line    int    `json:"line,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type UpsertPlaylistReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Review string `json:"review"`
+}
+
+type PlaylistReviewDTO struct {
+	ID             uuid.UUID    `json:"id"`
+	PlaylistID     uuid.UUID    `json:"playlistId"`
+	UserID         uuid.UUID    `json:"userId"`
+	Name           string       `json:"name,omitempty"`
+	Username       string       `json:"username,omitempty"`
+	UserProfileUrl string       `json:"userProfilePic,omitempty"`
+	UserColor      models.Color `json:"userColor,omitempty"`
+	Rating         int          `json:"rating"`
+	Review         string       `json:"review"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+type AddPlaylistPrerequisiteRequest struct {
+	PrerequisiteID uuid.UUID `json:"prerequisiteId" binding:"required"`
+}
+
+type PlaylistPrerequisiteDTO struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Code            string    `json:"code"`
+	ThumbnailURL    string    `json:"thumbnailUrl"`
+	TotalTopics     int       `json:"totalTopics"`
+	CompletedTopics int       `json:"completedTopics"`
+	IsCompleted     bool      `json:"isCompleted"`
+}
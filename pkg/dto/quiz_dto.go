@@ -1,8 +1,28 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/easc01/mindo-server/internal/models"
+	"github.com/easc01/mindo-server/pkg/utils/util"
+	"github.com/google/uuid"
+)
+
+// GenerateQuizParams asks the generator for a quiz, the videos and notes
+// headings of the topic are what explanations may reference
 type GenerateQuizParams struct {
-	TopicName     string `json:"topicName"`
-	QuestionCount int    `json:"questionCount"`
+	TopicName             string              `json:"topicName"`
+	QuestionCount         int                 `json:"questionCount"`
+	QuestionTypes         []string            `json:"questionTypes,omitempty"`
+	IncludeExplanations   bool                `json:"includeExplanations"`
+	Videos                []GenerateQuizVideo `json:"videos,omitempty"`
+	StudyMaterialHeadings []string            `json:"studyMaterialHeadings,omitempty"`
+}
+
+type GenerateQuizVideo struct {
+	VideoID         string `json:"videoId"`
+	Title           string `json:"title"`
+	DurationSeconds int    `json:"durationSeconds,omitempty"`
 }
 
 type GeneratedQuiz struct {
@@ -11,12 +31,42 @@ type GeneratedQuiz struct {
 	TopicName string                  `json:"topicName"`
 }
 
+// GeneratedQuizQuestion is one question as the generator returns it, which
+// answer fields are set depends on the type, no type means single choice
 type GeneratedQuizQuestion struct {
-	QuestionId     string                 `json:"questionId"`
-	CorrectOption  int                    `json:"correctOption"`
-	Options        []GeneratedQuizOptions `json:"options"`
-	Question       string                 `json:"question"`
-	QuestionNumber int                    `json:"questionNumber"`
+	QuestionId      string                   `json:"questionId"`
+	Type            string                   `json:"type"`
+	CorrectOption   int                      `json:"correctOption"`
+	CorrectOptions  []int                    `json:"correctOptions"`
+	NumericAnswer   *float64                 `json:"numericAnswer"`
+	Tolerance       float64                  `json:"tolerance"`
+	AcceptedAnswers []string                 `json:"acceptedAnswers"`
+	Pattern         string                   `json:"pattern"`
+	Options         []GeneratedQuizOptions   `json:"options"`
+	Question        string                   `json:"question"`
+	QuestionNumber  int                      `json:"questionNumber"`
+	Explanation     string                   `json:"explanation"`
+	References      []GeneratedQuizReference `json:"references"`
+}
+
+// GeneratedQuizReference points at a video of the topic by its provider id or
+// at a heading of the topic's notes
+type GeneratedQuizReference struct {
+	Type         string `json:"type"`
+	VideoID      string `json:"videoId"`
+	StartSeconds int    `json:"startSeconds"`
+	Heading      string `json:"heading"`
+}
+
+// QuizAnswerKey is the stored answer of questions that are not single choice,
+// Options is the set of a multi select question or the sequence of an
+// ordering one
+type QuizAnswerKey struct {
+	Options   []int    `json:"options,omitempty"`
+	Value     *float64 `json:"value,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+	Accepted  []string `json:"accepted,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
 }
 
 type GeneratedQuizOptions struct {
@@ -24,17 +74,186 @@ type GeneratedQuizOptions struct {
 	OptionNumber int    `json:"optionNumber"`
 }
 
+// QuizDTO is a quiz as served to a learner, answers are only revealed when
+// the session is verified
+type QuizDTO struct {
+	QuizID           uuid.UUID            `json:"quizId"`
+	SessionID        uuid.UUID            `json:"sessionId"`
+	TopicName        string               `json:"topicName"`
+	StartedAt        time.Time            `json:"startedAt"`
+	DeadlineAt       *time.Time           `json:"deadlineAt,omitempty"`
+	TimeLimitSeconds int                  `json:"timeLimitSeconds,omitempty"`
+	Questions        []QuizQuestionDTO    `json:"questions"`
+	Answers          []VerifyQuizQuestion `json:"answers,omitempty"`
+}
+
+type QuizQuestionDTO struct {
+	QuestionID     uuid.UUID       `json:"questionId"`
+	Type           string          `json:"type"`
+	QuestionNumber int             `json:"questionNumber"`
+	Question       string          `json:"question"`
+	Options        []QuizOptionDTO `json:"options"`
+}
+
+type QuizOptionDTO struct {
+	Option       string `json:"option"`
+	OptionNumber int    `json:"optionNumber"`
+}
+
 type VerifyQuizParams struct {
+	SessionId string               `json:"sessionId" binding:"required"`
 	QuizId    string               `json:"quizId"`
 	Questions []VerifyQuizQuestion `json:"questions"`
 }
 
+// VerifyQuizQuestion is the answer to one question, choice questions set
+// AttemptedOption, multi select and ordering ones AttemptedOptions, numeric
+// ones Value and short text ones Text
 type VerifyQuizQuestion struct {
-	QuestionId      string `json:"questionId"`
-	AttemptedOption int    `json:"attemptedOption"`
+	QuestionId       string   `json:"questionId"`
+	AttemptedOption  int      `json:"attemptedOption,omitempty"`
+	AttemptedOptions []int    `json:"attemptedOptions,omitempty"`
+	Value            *float64 `json:"value,omitempty"`
+	Text             string   `json:"text,omitempty"`
 }
 
 type VerifyQuizResults struct {
-	Grade string  `json:"grade"`
-	Marks float64 `json:"marks"`
+	AttemptID     uuid.UUID           `json:"attemptId"`
+	IsLate        bool                `json:"isLate"`
+	Grade         string              `json:"grade"`
+	Marks         float64             `json:"marks"`
+	ObtainedMarks float64             `json:"obtainedMarks"`
+	TotalMarks    float64             `json:"totalMarks"`
+	Answers       []VerifiedAnswerDTO `json:"answers"`
+}
+
+// SaveQuizAnswersRequest autosaves answers of an open session, option numbers
+// are the ones the session served
+type SaveQuizAnswersRequest struct {
+	Questions []VerifyQuizQuestion `json:"questions" binding:"required,min=1"`
+}
+
+type QuizSessionDraftDTO struct {
+	SessionID    uuid.UUID  `json:"sessionId"`
+	SavedAnswers int        `json:"savedAnswers"`
+	DeadlineAt   *time.Time `json:"deadlineAt,omitempty"`
+}
+
+type VerifiedAnswerDTO struct {
+	QuestionID uuid.UUID `json:"questionId"`
+	Type       string    `json:"type"`
+	QuizAnswerRevealDTO
+	IsCorrect bool `json:"isCorrect"`
+	// set for wrong answers only
+	Explanation string             `json:"explanation,omitempty"`
+	References  []QuizReferenceDTO `json:"references,omitempty"`
+}
+
+// QuizReferenceDTO is where a topic covers a question, a video from a
+// timestamp or a section of the topic's study material
+type QuizReferenceDTO struct {
+	Type         string     `json:"type"`
+	VideoID      string     `json:"videoId,omitempty"`
+	Provider     string     `json:"provider,omitempty"`
+	StartSeconds int        `json:"startSeconds,omitempty"`
+	TopicID      *uuid.UUID `json:"topicId,omitempty"`
+	Heading      string     `json:"heading,omitempty"`
+	Title        string     `json:"title,omitempty"`
+}
+
+// QuizAnswerRevealDTO puts the learner's answer next to the correct one, only
+// the fields of the question type are set
+type QuizAnswerRevealDTO struct {
+	AttemptedOption  int      `json:"attemptedOption,omitempty"`
+	CorrectOption    int      `json:"correctOption,omitempty"`
+	AttemptedOptions []int    `json:"attemptedOptions,omitempty"`
+	CorrectOptions   []int    `json:"correctOptions,omitempty"`
+	Value            *float64 `json:"value,omitempty"`
+	CorrectValue     *float64 `json:"correctValue,omitempty"`
+	Tolerance        float64  `json:"tolerance,omitempty"`
+	Text             string   `json:"text,omitempty"`
+	AcceptedAnswers  []string `json:"acceptedAnswers,omitempty"`
+}
+
+type QuizAttemptDTO struct {
+	ID             uuid.UUID `json:"id"`
+	QuizID         uuid.UUID `json:"quizId"`
+	QuizName       string    `json:"quizName"`
+	Marks          float64   `json:"marks"`
+	Grade          string    `json:"grade"`
+	CorrectAnswers int       `json:"correctAnswers"`
+	TotalQuestions int       `json:"totalQuestions"`
+	AttemptedAt    time.Time `json:"attemptedAt"`
+}
+
+type QuizAttemptDetailsDTO struct {
+	QuizAttemptDTO
+	Questions []QuizAttemptQuestionDTO `json:"questions"`
+}
+
+type QuizAttemptQuestionDTO struct {
+	QuestionID uuid.UUID `json:"questionId"`
+	Type       string    `json:"type"`
+	Question   string    `json:"question"`
+	Options    []string  `json:"options"`
+	QuizAnswerRevealDTO
+	IsCorrect bool `json:"isCorrect"`
+}
+
+// UpdateQuizScoringRequest sets how a quiz is marked, weights of questions not
+// listed stay as they are
+type UpdateQuizScoringRequest struct {
+	NegativeMarking *float64            `json:"negativeMarking" binding:"required,min=0,max=1"`
+	GradeBands      []GradeBandDTO      `json:"gradeBands"      binding:"omitempty,dive"`
+	QuestionWeights []QuestionWeightDTO `json:"questionWeights" binding:"omitempty,dive"`
+}
+
+type GradeBandDTO struct {
+	Grade      string   `json:"grade"      binding:"required,max=4"`
+	MinPercent *float64 `json:"minPercent" binding:"required,min=0,max=100"`
+}
+
+type QuestionWeightDTO struct {
+	QuestionID uuid.UUID `json:"questionId" binding:"required"`
+	Weight     float64   `json:"weight"     binding:"required,gt=0"`
+}
+
+type QuizScoringDTO struct {
+	QuizID          uuid.UUID           `json:"quizId"`
+	NegativeMarking float64             `json:"negativeMarking"`
+	GradeBands      []util.GradeBand    `json:"gradeBands"`
+	QuestionWeights []QuestionWeightDTO `json:"questionWeights"`
+}
+
+// UpdateQuizTimeLimitRequest makes a quiz timed, no limit makes it untimed
+type UpdateQuizTimeLimitRequest struct {
+	TimeLimitSeconds *int `json:"timeLimitSeconds" binding:"omitempty,min=30"`
+}
+
+type QuizTimeLimitDTO struct {
+	QuizID           uuid.UUID `json:"quizId"`
+	TimeLimitSeconds *int      `json:"timeLimitSeconds"`
+}
+
+// LeaderboardDTO ranks learners by their first attempts, Me is the requesting
+// learner's own row whether or not it is on the page
+type LeaderboardDTO struct {
+	Period  string                `json:"period,omitempty"`
+	Entries []LeaderboardEntryDTO `json:"entries"`
+	Me      *LeaderboardEntryDTO  `json:"me,omitempty"`
+}
+
+// LeaderboardEntryDTO is one learner on a board, equal scores rank the faster
+// completion first
+type LeaderboardEntryDTO struct {
+	Rank              int          `json:"rank"`
+	UserID            uuid.UUID    `json:"userId"`
+	Username          string       `json:"username"`
+	Name              string       `json:"name"`
+	ProfilePictureUrl string       `json:"profilePictureUrl"`
+	Color             models.Color `json:"color"`
+	Score             float64      `json:"score"`
+	QuizzesCompleted  int          `json:"quizzesCompleted,omitempty"`
+	CompletionSeconds int64        `json:"completionSeconds"`
+	SubmittedAt       *time.Time   `json:"submittedAt,omitempty"`
 }
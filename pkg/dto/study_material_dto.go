@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StudyMaterialDTO struct {
+	ID           uuid.UUID `json:"id"`
+	TopicID      uuid.UUID `json:"topicId"`
+	Title        string    `json:"title"`
+	Content      string    `json:"content"`
+	ContentHTML  string    `json:"contentHtml"`
+	IsGenerated  bool      `json:"isGenerated"`
+	IsBookmarked bool      `json:"isBookmarked"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedBy    uuid.UUID `json:"updatedBy"`
+}
+
+type UpsertStudyMaterialRequest struct {
+	Title   string `json:"title" binding:"required,max=255"`
+	Content string `json:"content" binding:"required"`
+}
+
+type StudyMaterialBookmarkDTO struct {
+	ID           uuid.UUID `json:"id"`
+	TopicID      uuid.UUID `json:"topicId"`
+	TopicName    string    `json:"topicName"`
+	PlaylistID   uuid.UUID `json:"playlistId"`
+	PlaylistName string    `json:"playlistName"`
+	Title        string    `json:"title"`
+	Excerpt      string    `json:"excerpt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	BookmarkedAt time.Time `json:"bookmarkedAt"`
+}
+
+type GenerateStudyMaterialParams struct {
+	TopicName    string `json:"topicName"`
+	PlaylistName string `json:"subject"`
+}
+
+type GeneratedStudyMaterial struct {
+	Title   string `json:"title"`
+	Content string `json:"notes"`
+}
@@ -48,3 +48,58 @@ type YouTubeThumbnail struct {
 	Width  int    `json:"width"`
 	Height int    `json:"height"`
 }
+
+type YouTubeVideosResponse struct {
+	Kind  string                 `json:"kind"`
+	Etag  string                 `json:"etag"`
+	Items []YouTubeVideoListItem `json:"items"`
+}
+
+type YouTubeVideoListItem struct {
+	ID             string                `json:"id"`
+	Snippet        YouTubeSnippet        `json:"snippet"`
+	ContentDetails YouTubeContentDetails `json:"contentDetails"`
+	Statistics     YouTubeStatistics     `json:"statistics"`
+}
+
+type YouTubeContentDetails struct {
+	// ISO 8601 duration, e.g. PT12M31S
+	Duration   string `json:"duration"`
+	Definition string `json:"definition"`
+	// "true" or "false", the api sends it as a string
+	Caption string `json:"caption"`
+}
+
+// YouTubeStatistics counts arrive as strings, missing when the owner hides them
+type YouTubeStatistics struct {
+	ViewCount    string `json:"viewCount"`
+	LikeCount    string `json:"likeCount"`
+	CommentCount string `json:"commentCount"`
+}
+
+type YouTubeErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+type YoutubeQuotaDTO struct {
+	Date       time.Time              `json:"date"`
+	DailyLimit int                    `json:"dailyLimit"`
+	Reserve    int                    `json:"reserve"`
+	Used       int                    `json:"used"`
+	Remaining  int                    `json:"remaining"`
+	Exhausted  bool                   `json:"exhausted"`
+	History    []YoutubeQuotaUsageDTO `json:"history"`
+}
+
+type YoutubeQuotaUsageDTO struct {
+	Date     time.Time `json:"date"`
+	Endpoint string    `json:"endpoint"`
+	Units    int       `json:"units"`
+	Calls    int       `json:"calls"`
+}
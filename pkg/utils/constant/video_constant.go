@@ -0,0 +1,31 @@
+package constant
+
+// Video providers, the value is stored in youtube_video.provider
+const (
+	VideoProviderYoutube  = "youtube"
+	VideoProviderPeerTube = "peertube"
+	VideoProviderFixture  = "fixture"
+)
+
+// Reasons a learner can report a video for, stored in video_report.reason
+const (
+	VideoReportBroken        = "broken"
+	VideoReportOffTopic      = "off_topic"
+	VideoReportLowQuality    = "low_quality"
+	VideoReportWrongLanguage = "wrong_language"
+	VideoReportInappropriate = "inappropriate"
+	VideoReportOther         = "other"
+)
+
+// Admin decisions on reported videos, stored in video_report.resolution
+const (
+	VideoReportDismiss = "dismiss"
+	VideoReportHide    = "hide"
+	VideoReportBan     = "ban"
+)
+
+// Video ban kinds, the value is stored in video_ban.kind
+const (
+	VideoBanKindVideo   = "video"
+	VideoBanKindChannel = "channel"
+)
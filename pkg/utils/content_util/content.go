@@ -0,0 +1,184 @@
+package contentutil
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// renderCacheSize bounds how many rendered documents are kept, chat messages
+// repeat a lot so a small cache already saves most renders
+const renderCacheSize = 4096
+
+// Rendered is user markdown turned into HTML safe to inject into a page, and
+// the same content as plain text for search and previews
+type Rendered struct {
+	HTML string
+	Text string
+}
+
+// markdown never passes raw HTML through, the policy below still strips
+// anything a parser bug would let out
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Table,
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+)
+
+var htmlPolicy = newHTMLPolicy()
+
+var textPolicy = bluemonday.StrictPolicy()
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+var blockEnd = regexp.MustCompile(`(?i)</(p|h[1-6]|li|td|th|pre|blockquote|div)>|<br\s*/?>`)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	policy.AllowAttrs("type", "checked", "disabled").OnElements("input")
+	policy.RequireNoReferrerOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+var cache = struct {
+	sync.Mutex
+	order   *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}{order: list.New(), entries: map[[sha256.Size]byte]*list.Element{}}
+
+type cacheEntry struct {
+	key      [sha256.Size]byte
+	rendered Rendered
+}
+
+// Render parses markdown into sanitized HTML and plain text, results are
+// cached by content so the same source is only rendered once
+func Render(source string) Rendered {
+	if strings.TrimSpace(source) == "" {
+		return Rendered{}
+	}
+
+	key := sha256.Sum256([]byte(source))
+
+	cache.Lock()
+	if element, ok := cache.entries[key]; ok {
+		cache.order.MoveToFront(element)
+		rendered := element.Value.(*cacheEntry).rendered
+		cache.Unlock()
+		return rendered
+	}
+	cache.Unlock()
+
+	rendered := render(source)
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		cache.order.MoveToFront(element)
+		return rendered
+	}
+
+	cache.entries[key] = cache.order.PushFront(&cacheEntry{key: key, rendered: rendered})
+	if cache.order.Len() > renderCacheSize {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return rendered
+}
+
+// HTML returns the sanitized HTML of a markdown source
+func HTML(source string) string {
+	return Render(source).HTML
+}
+
+// PlainText returns a markdown source with all formatting removed
+func PlainText(source string) string {
+	return Render(source).Text
+}
+
+// Excerpt returns the first runes of the plain text, cut on a word boundary
+func Excerpt(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+// Headings returns the text of every heading in a markdown source, in
+// document order
+func Headings(source string) []string {
+	src := []byte(source)
+	document := markdown.Parser().Parse(text.NewReader(src))
+
+	var headings []string
+	ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var title strings.Builder
+		ast.Walk(heading, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+			if segment, ok := child.(*ast.Text); ok && entering {
+				title.Write(segment.Segment.Value(src))
+				if segment.SoftLineBreak() {
+					title.WriteByte(' ')
+				}
+			}
+			return ast.WalkContinue, nil
+		})
+
+		if value := strings.TrimSpace(title.String()); value != "" {
+			headings = append(headings, value)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+
+	return headings
+}
+
+func render(source string) Rendered {
+	var buf bytes.Buffer
+
+	// markdown that fails to parse is shown as escaped text rather than dropped
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		buf.Reset()
+		buf.WriteString("<p>" + html.EscapeString(source) + "</p>")
+	}
+
+	safeHTML := htmlPolicy.SanitizeBytes(buf.Bytes())
+
+	// strip tags from the rendered HTML so markdown syntax does not leak into
+	// text, block ends become spaces to keep paragraphs from running together
+	spaced := blockEnd.ReplaceAll(safeHTML, []byte("$0 "))
+	text := html.UnescapeString(string(textPolicy.SanitizeBytes(spaced)))
+
+	return Rendered{
+		HTML: string(safeHTML),
+		Text: strings.TrimSpace(whitespace.ReplaceAllString(text, " ")),
+	}
+}
@@ -1,20 +1,32 @@
 package route
 
 const (
-	Api         = "/api"
-	User        = "/users"
-	Admin       = "/admins"
-	Auth        = "/auth"
-	Interest    = "/interests"
-	Refresh     = "/refresh"
-	Google      = "/google"
-	Playlists   = "/playlists"
-	Topics      = "/topics"
-	SignIn      = "/sign-in"
-	SignUp      = "/sign-up"
-	Communities = "/communities"
-	Messages    = "/messages"
-	Quizzes     = "/quizzes"
+	Api            = "/api"
+	User           = "/users"
+	Admin          = "/admins"
+	Auth           = "/auth"
+	Interest       = "/interests"
+	Refresh        = "/refresh"
+	Google         = "/google"
+	Playlists      = "/playlists"
+	Topics         = "/topics"
+	SignIn         = "/sign-in"
+	SignUp         = "/sign-up"
+	Communities    = "/communities"
+	Messages       = "/messages"
+	Quizzes        = "/quizzes"
+	Reviews        = "/reviews"
+	Prerequisites  = "/prerequisites"
+	LearningPaths  = "/learning-paths"
+	Certificates   = "/certificates"
+	Certificate    = "/certificate"
+	Youtube        = "/youtube"
+	Quota          = "/quota"
+	VideoBans      = "/video-bans"
+	VideoReports   = "/video-reports"
+	StudyMaterials = "/study-materials"
+	Bookmarks      = "/bookmarks"
+	Flashcards     = "/flashcards"
 )
 
 func GetRefreshRoute() string {
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Coalescer runs at most one call per key at a time, callers arriving while a
+// call for their key is in flight wait for it and share its result
+type Coalescer[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall[T]
+}
+
+type coalescedCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Do runs fn for key unless a call for key is already running, shared reports
+// whether the result came from another caller's run
+func (g *Coalescer[T]) Do(key string, fn func() (T, error)) (value T, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*coalescedCall[T])
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.value, call.err, true
+	}
+
+	call := &coalescedCall[T]{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		// waiters must not read a panicking call as an empty success, the
+		// leader still panics
+		recovered := recover()
+		if recovered != nil {
+			call.err = fmt.Errorf("coalesced call for %s panicked, %v", key, recovered)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	call.value, call.err = fn()
+	return call.value, call.err, false
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/easc01/mindo-server/pkg/utils/constant"
 	"github.com/google/uuid"
@@ -22,6 +23,16 @@ func GetSQLNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != constant.Blank}
 }
 
+// Returns sql.NullInt32 for a number, zero stores as null
+func GetSQLNullInt32(n int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(n), Valid: n != 0}
+}
+
+// Returns sql.NullInt64 for a number, zero stores as null
+func GetSQLNullInt64(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: n != 0}
+}
+
 // Returns sql.NullString for a string
 func GetNullUUID(s uuid.UUID) uuid.NullUUID {
 	return uuid.NullUUID{UUID: s, Valid: true}
@@ -78,25 +89,40 @@ func ReverseSlice[T any](items []T) {
 	}
 }
 
-func GetGrade(totalAns, correctAns int) string {
-	if totalAns == 0 {
-		return "N/A"
+// GradeBand awards a grade to scores at or above MinPercent
+type GradeBand struct {
+	Grade      string  `json:"grade"`
+	MinPercent float64 `json:"minPercent"`
+}
+
+// DefaultGradeBands grade quizzes that do not configure their own bands
+var DefaultGradeBands = []GradeBand{
+	{Grade: "A+", MinPercent: 90},
+	{Grade: "A", MinPercent: 80},
+	{Grade: "B", MinPercent: 70},
+	{Grade: "C", MinPercent: 60},
+	{Grade: "D", MinPercent: 50},
+	{Grade: "F", MinPercent: 0},
+}
+
+// GetGradeForPercentage returns the grade of the highest band the percentage
+// reaches, scores below every band get the lowest one
+func GetGradeForPercentage(percentage float64, bands []GradeBand) string {
+	if len(bands) == 0 {
+		bands = DefaultGradeBands
 	}
 
-	percentage := float64(correctAns) / float64(totalAns) * 100
-
-	switch {
-	case percentage >= 90:
-		return "A+"
-	case percentage >= 80:
-		return "A"
-	case percentage >= 70:
-		return "B"
-	case percentage >= 60:
-		return "C"
-	case percentage >= 50:
-		return "D"
-	default:
-		return "F"
+	sorted := make([]GradeBand, len(bands))
+	copy(sorted, bands)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinPercent > sorted[j].MinPercent
+	})
+
+	for _, band := range sorted {
+		if percentage >= band.MinPercent {
+			return band.Grade
+		}
 	}
+
+	return sorted[len(sorted)-1].Grade
 }